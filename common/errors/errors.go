@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	stderrors "errors"
+	"fmt"
+)
 
 // ErrorCode 에러 코드 정의
 type ErrorCode string
@@ -62,7 +65,8 @@ func Wrap(code ErrorCode, message string, cause error) *DomainError {
 
 // IsRetryable 재시도 가능한 에러인지 판단
 func IsRetryable(err error) bool {
-	if domainErr, ok := err.(*DomainError); ok {
+	var domainErr *DomainError
+	if stderrors.As(err, &domainErr) {
 		switch domainErr.Code {
 		case ErrCodeDatabaseError, ErrCodeNetworkError, ErrCodeTimeoutError:
 			return true
@@ -73,7 +77,8 @@ func IsRetryable(err error) bool {
 
 // IsBusinessError 비즈니스 에러인지 판단 (재시도 불필요)
 func IsBusinessError(err error) bool {
-	if domainErr, ok := err.(*DomainError); ok {
+	var domainErr *DomainError
+	if stderrors.As(err, &domainErr) {
 		switch domainErr.Code {
 		case ErrCodePaymentDeclined, ErrCodeOutOfStock, ErrCodeInsufficientBalance,
 			ErrCodeInvalidOrder, ErrCodeOrderNotFound, ErrCodeDuplicateRequest,
@@ -86,7 +91,8 @@ func IsBusinessError(err error) bool {
 
 // IsCode 특정 에러 코드인지 확인
 func IsCode(err error, code ErrorCode) bool {
-	if domainErr, ok := err.(*DomainError); ok {
+	var domainErr *DomainError
+	if stderrors.As(err, &domainErr) {
 		return domainErr.Code == code
 	}
 	return false
@@ -27,11 +27,85 @@ const (
 	EventDeliveryFailed  EventType = "delivery.failed.v1"
 )
 
+// AllEventTypes 정의된 모든 이벤트 타입
+func AllEventTypes() []EventType {
+	return []EventType{
+		EventOrderCreated, EventOrderCompleted, EventOrderCanceled, EventOrderFailed,
+		EventPaymentCompleted, EventPaymentFailed, EventPaymentRefunded,
+		EventStockReserved, EventStockReservationFailed, EventStockRestored,
+		EventDeliveryStarted, EventDeliveryFailed,
+	}
+}
+
 // Event 이벤트 인터페이스
 type Event interface {
 	GetEventType() EventType
 	GetEventID() string
 	GetCorrelationID() string
+	GetCausationID() string
+	GetSchemaVersion() int
+}
+
+// TypeOf 이벤트 구조체에 대응하는 이벤트 타입 (값이 비어 있어도 구조체 타입으로 판단)
+func TypeOf(evt Event) EventType {
+	switch evt.(type) {
+	case OrderCreatedEvent, *OrderCreatedEvent:
+		return EventOrderCreated
+	case OrderCompletedEvent, *OrderCompletedEvent:
+		return EventOrderCompleted
+	case OrderCanceledEvent, *OrderCanceledEvent:
+		return EventOrderCanceled
+	case OrderFailedEvent, *OrderFailedEvent:
+		return EventOrderFailed
+	case PaymentCompletedEvent, *PaymentCompletedEvent:
+		return EventPaymentCompleted
+	case PaymentFailedEvent, *PaymentFailedEvent:
+		return EventPaymentFailed
+	case PaymentRefundedEvent, *PaymentRefundedEvent:
+		return EventPaymentRefunded
+	case StockReservedEvent, *StockReservedEvent:
+		return EventStockReserved
+	case StockReservationFailedEvent, *StockReservationFailedEvent:
+		return EventStockReservationFailed
+	case StockRestoredEvent, *StockRestoredEvent:
+		return EventStockRestored
+	case DeliveryStartedEvent, *DeliveryStartedEvent:
+		return EventDeliveryStarted
+	case DeliveryFailedEvent, *DeliveryFailedEvent:
+		return EventDeliveryFailed
+	}
+	return evt.GetEventType()
+}
+
+// OrderIDOf 이벤트가 속한 주문(SAGA) ID (주문 ID가 없는 이벤트면 false)
+func OrderIDOf(evt Event) (int64, bool) {
+	switch e := evt.(type) {
+	case OrderCreatedEvent:
+		return e.OrderID, true
+	case OrderCompletedEvent:
+		return e.OrderID, true
+	case OrderCanceledEvent:
+		return e.OrderID, true
+	case OrderFailedEvent:
+		return e.OrderID, true
+	case PaymentCompletedEvent:
+		return e.OrderID, true
+	case PaymentFailedEvent:
+		return e.OrderID, true
+	case PaymentRefundedEvent:
+		return e.OrderID, true
+	case StockReservedEvent:
+		return e.OrderID, true
+	case StockReservationFailedEvent:
+		return e.OrderID, true
+	case StockRestoredEvent:
+		return e.OrderID, true
+	case DeliveryStartedEvent:
+		return e.OrderID, true
+	case DeliveryFailedEvent:
+		return e.OrderID, true
+	}
+	return 0, false
 }
 
 // BaseEvent 모든 이벤트의 기본 구조
@@ -40,7 +114,8 @@ type BaseEvent struct {
 	EventType     EventType `json:"eventType"`
 	SchemaVersion int       `json:"schemaVersion"`
 	OccurredAt    time.Time `json:"occurredAt"`
-	CorrelationID string    `json:"correlationId"` // SAGA ID로 사용
+	CorrelationID string    `json:"correlationId"`         // SAGA ID로 사용
+	CausationID   string    `json:"causationId,omitempty"` // 이 이벤트를 발생시킨 이벤트 ID
 }
 
 // GetEventType 이벤트 타입 반환
@@ -58,6 +133,16 @@ func (e BaseEvent) GetCorrelationID() string {
 	return e.CorrelationID
 }
 
+// GetSchemaVersion 스키마 버전 반환
+func (e BaseEvent) GetSchemaVersion() int {
+	return e.SchemaVersion
+}
+
+// GetCausationID 원인 이벤트 ID 반환
+func (e BaseEvent) GetCausationID() string {
+	return e.CausationID
+}
+
 // OrderCreatedEvent 주문 생성 이벤트
 type OrderCreatedEvent struct {
 	BaseEvent
@@ -0,0 +1,204 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HTTP 멱등성 헤더
+const (
+	// HeaderIdempotencyKey 클라이언트가 요청마다 지정하는 멱등성 키
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed 저장된 응답을 재생했을 때 응답에 붙는 헤더
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// MaxKeyLength 허용하는 멱등성 키 최대 길이 (orders.idempotency_key VARCHAR(64) 와 같음)
+const MaxKeyLength = 64
+
+// HTTPConfig HTTP 멱등성 미들웨어 설정
+type HTTPConfig struct {
+	// TTL 응답을 보관하는 기간 (이 기간 안의 재시도에 같은 응답을 재생)
+	TTL time.Duration
+	// Lease 요청 처리 중 키를 점유하는 시간 (처리 중 같은 키로 들어온 요청은 409)
+	Lease time.Duration
+	// MaxBodyBytes 지문 계산을 위해 읽는 요청 본문 최대 크기
+	MaxBodyBytes int64
+}
+
+// DefaultHTTPConfig 기본 미들웨어 설정 (24시간 보관, 30초 점유, 1MB 본문)
+func DefaultHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		TTL:          24 * time.Hour,
+		Lease:        30 * time.Second,
+		MaxBodyBytes: 1 << 20,
+	}
+}
+
+// storedResponse 재생을 위해 저장하는 원래 응답
+type storedResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body"`
+}
+
+// Middleware Idempotency-Key 헤더가 있는 요청의 응답을 저장하고 같은 키의 재시도에 재생하는 미들웨어
+//
+//   - 처음 요청: 핸들러를 실행하고 상태 코드와 응답 본문을 TTL 동안 저장 (5xx 응답은 저장하지 않아 재시도 가능)
+//   - 같은 요청 재시도: 핸들러를 실행하지 않고 저장된 응답을 Idempotent-Replayed: true 와 함께 재생
+//   - 처리 중인 키로 다시 요청: 409 Conflict
+//   - 같은 키를 다른 요청(메서드, 경로, 본문)에 재사용: 422 Unprocessable Entity
+//
+// 헤더가 없거나 GET 처럼 안전한 메서드는 그대로 통과시킨다.
+func Middleware(store Store, config HTTPConfig, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderIdempotencyKey)
+			if key == "" || isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > MaxKeyLength {
+				respondError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxBodyBytes+1))
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			if int64(len(body)) > config.MaxBodyBytes {
+				respondError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := requestFingerprint(r, body)
+			storeKey := "http:" + key
+
+			claim, err := store.Claim(r.Context(), storeKey, config.Lease)
+			if err != nil {
+				logger.Error("failed to claim idempotency key", zap.String("idempotencyKey", key), zap.Error(err))
+				respondError(w, http.StatusServiceUnavailable, "idempotency store unavailable")
+				return
+			}
+
+			if !claim.Acquired {
+				if claim.State != StateCompleted {
+					respondError(w, http.StatusConflict, "a request with this Idempotency-Key is in progress")
+					return
+				}
+
+				var stored storedResponse
+				if err := json.Unmarshal(claim.Result, &stored); err != nil {
+					logger.Error("failed to decode stored response", zap.String("idempotencyKey", key), zap.Error(err))
+					respondError(w, http.StatusInternalServerError, "failed to replay stored response")
+					return
+				}
+				if stored.Fingerprint != fingerprint {
+					respondError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+					return
+				}
+
+				logger.Info("replaying stored response", zap.String("idempotencyKey", key), zap.Int("status", stored.Status))
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+				w.Header().Set(HeaderIdempotentReplayed, "true")
+				w.WriteHeader(stored.Status)
+				w.Write(stored.Body)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// 클라이언트 연결이 끊겨도 결과는 기록되도록 취소되지 않는 컨텍스트 사용
+			ctx := context.WithoutCancel(r.Context())
+
+			if recorder.status >= http.StatusInternalServerError {
+				// 서버 오류는 재시도가 다시 처리할 수 있도록 점유만 해제
+				if err := store.Release(ctx, storeKey, claim.Token); err != nil {
+					logger.Warn("failed to release idempotency key", zap.String("idempotencyKey", key), zap.Error(err))
+				}
+				return
+			}
+
+			result, err := json.Marshal(storedResponse{
+				Fingerprint: fingerprint,
+				Status:      recorder.status,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			})
+			if err != nil {
+				logger.Error("failed to encode response", zap.String("idempotencyKey", key), zap.Error(err))
+				return
+			}
+			if err := store.Complete(ctx, storeKey, claim.Token, result, config.TTL); err != nil {
+				logger.Warn("failed to store response", zap.String("idempotencyKey", key), zap.Error(err))
+			}
+		})
+	}
+}
+
+// requestFingerprint 메서드, 경로, 본문의 SHA-256 (JSON 본문은 공백 차이를 무시)
+func requestFingerprint(r *http.Request, body []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body); err == nil {
+		body = compact.Bytes()
+	}
+
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	io.WriteString(h, " ")
+	io.WriteString(h, r.URL.Path)
+	io.WriteString(h, "\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// responseRecorder 클라이언트에 응답을 쓰면서 상태 코드와 본문을 저장
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
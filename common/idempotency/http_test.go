@@ -0,0 +1,54 @@
+package idempotency_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"go.uber.org/zap"
+)
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	handler := idempotency.Middleware(idempotency.NewMemoryStore(), idempotency.DefaultHTTPConfig(), zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"orderId":1}`))
+		}))
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set(idempotency.HeaderIdempotencyKey, key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send("order-1", `{"amount": 100}`)
+	if first.Code != http.StatusCreated || first.Header().Get(idempotency.HeaderIdempotentReplayed) != "" {
+		t.Fatalf("expected original 201 response, got %d %v", first.Code, first.Header())
+	}
+
+	// 공백만 다른 같은 요청은 재생
+	replayed := send("order-1", `{"amount":100}`)
+	if replayed.Code != http.StatusCreated || replayed.Header().Get(idempotency.HeaderIdempotentReplayed) != "true" {
+		t.Fatalf("expected replayed 201 response, got %d %v", replayed.Code, replayed.Header())
+	}
+	if replayed.Body.String() != first.Body.String() {
+		t.Fatalf("expected body %q, got %q", first.Body.String(), replayed.Body.String())
+	}
+
+	if rec := send("order-1", `{"amount":200}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for different request, got %d", rec.Code)
+	}
+	if rec := send(strings.Repeat("k", idempotency.MaxKeyLength+1), `{"amount":100}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for too long key, got %d", rec.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, got %d", calls)
+	}
+}
@@ -0,0 +1,252 @@
+// Package idempotencytest idempotency.Store 구현이 공통으로 지켜야 하는 동작을 검증하는 적합성 테스트
+//
+// 각 저장소의 테스트에서 새 저장소를 만드는 함수를 넘겨 실행한다.
+//
+//	func TestMemoryStore(t *testing.T) {
+//	    idempotencytest.RunStoreConformance(t, func(t *testing.T) idempotency.Store {
+//	        return idempotency.NewMemoryStore()
+//	    })
+//	}
+package idempotencytest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+)
+
+// 테스트에 사용하는 시간 (Redis PEXPIRE 는 밀리초 단위이므로 여유를 둔다)
+const (
+	longLease  = time.Minute
+	shortLease = 100 * time.Millisecond
+	expiryWait = 300 * time.Millisecond
+)
+
+// RunStoreConformance newStore 가 만드는 저장소가 Store 계약을 지키는지 검증
+//
+// 키는 매번 새로 만들기 때문에 같은 백엔드를 여러 하위 테스트가 공유해도 된다.
+func RunStoreConformance(t *testing.T, newStore func(t *testing.T) idempotency.Store) {
+	t.Run("FirstClaimAcquires", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		claim := mustClaim(t, ctx, store, key, longLease)
+		if !claim.Acquired || claim.State != idempotency.StateInProgress {
+			t.Fatalf("expected acquired IN_PROGRESS claim, got %+v", claim)
+		}
+		if claim.Token <= 0 {
+			t.Fatalf("expected positive token, got %d", claim.Token)
+		}
+	})
+
+	t.Run("ClaimWhileInProgress", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		first := mustClaim(t, ctx, store, key, longLease)
+		second := mustClaim(t, ctx, store, key, longLease)
+		if second.Acquired || second.State != idempotency.StateInProgress {
+			t.Fatalf("expected IN_PROGRESS claim not acquired, got %+v", second)
+		}
+		if second.Token != first.Token {
+			t.Fatalf("expected token %d, got %d", first.Token, second.Token)
+		}
+	})
+
+	t.Run("CompleteStoresResult", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+		result := []byte(`{"status":"ok"}`)
+
+		claim := mustClaim(t, ctx, store, key, longLease)
+		if err := store.Complete(ctx, key, claim.Token, result, longLease); err != nil {
+			t.Fatalf("failed to complete: %v", err)
+		}
+
+		again := mustClaim(t, ctx, store, key, longLease)
+		if again.Acquired || again.State != idempotency.StateCompleted {
+			t.Fatalf("expected COMPLETED claim not acquired, got %+v", again)
+		}
+		if !bytes.Equal(again.Result, result) {
+			t.Fatalf("expected result %q, got %q", result, again.Result)
+		}
+	})
+
+	t.Run("CompleteTwice", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		claim := mustClaim(t, ctx, store, key, longLease)
+		if err := store.Complete(ctx, key, claim.Token, nil, longLease); err != nil {
+			t.Fatalf("failed to complete: %v", err)
+		}
+		if err := store.Complete(ctx, key, claim.Token, nil, longLease); !errors.Is(err, idempotency.ErrClaimLost) {
+			t.Fatalf("expected ErrClaimLost, got %v", err)
+		}
+	})
+
+	t.Run("ReleaseAllowsReclaim", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		first := mustClaim(t, ctx, store, key, longLease)
+		if err := store.Release(ctx, key, first.Token); err != nil {
+			t.Fatalf("failed to release: %v", err)
+		}
+
+		second := mustClaim(t, ctx, store, key, longLease)
+		if !second.Acquired {
+			t.Fatalf("expected claim to be acquired after release, got %+v", second)
+		}
+		if second.Token <= first.Token {
+			t.Fatalf("expected token greater than %d, got %d", first.Token, second.Token)
+		}
+	})
+
+	t.Run("ReleaseCompletedIsNoop", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		claim := mustClaim(t, ctx, store, key, longLease)
+		if err := store.Complete(ctx, key, claim.Token, []byte("done"), longLease); err != nil {
+			t.Fatalf("failed to complete: %v", err)
+		}
+		if err := store.Release(ctx, key, claim.Token); err != nil {
+			t.Fatalf("failed to release: %v", err)
+		}
+
+		again := mustClaim(t, ctx, store, key, longLease)
+		if again.Acquired || again.State != idempotency.StateCompleted {
+			t.Fatalf("expected COMPLETED claim to survive release, got %+v", again)
+		}
+	})
+
+	t.Run("LeaseExpiryAllowsTakeover", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		stale := mustClaim(t, ctx, store, key, shortLease)
+		time.Sleep(expiryWait)
+
+		fresh := mustClaim(t, ctx, store, key, longLease)
+		if !fresh.Acquired {
+			t.Fatalf("expected claim to be acquired after lease expiry, got %+v", fresh)
+		}
+		if fresh.Token <= stale.Token {
+			t.Fatalf("expected token greater than %d, got %d", stale.Token, fresh.Token)
+		}
+
+		// 넘겨준 처리자의 완료와 해제는 새 점유에 영향을 주지 않아야 한다
+		if err := store.Complete(ctx, key, stale.Token, []byte("stale"), longLease); !errors.Is(err, idempotency.ErrClaimLost) {
+			t.Fatalf("expected ErrClaimLost for stale token, got %v", err)
+		}
+		if err := store.Release(ctx, key, stale.Token); err != nil {
+			t.Fatalf("failed to release stale token: %v", err)
+		}
+
+		current := mustClaim(t, ctx, store, key, longLease)
+		if current.Acquired || current.State != idempotency.StateInProgress || current.Token != fresh.Token {
+			t.Fatalf("expected claim with token %d to be kept, got %+v", fresh.Token, current)
+		}
+		if err := store.Complete(ctx, key, fresh.Token, []byte("fresh"), longLease); err != nil {
+			t.Fatalf("failed to complete fresh claim: %v", err)
+		}
+	})
+
+	t.Run("CompleteAfterLeaseExpiry", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		// 넘겨받은 처리자가 없어도 lease 가 지난 점유는 완료할 수 없다
+		stale := mustClaim(t, ctx, store, key, shortLease)
+		time.Sleep(expiryWait)
+
+		if err := store.Complete(ctx, key, stale.Token, []byte("stale"), longLease); !errors.Is(err, idempotency.ErrClaimLost) {
+			t.Fatalf("expected ErrClaimLost after lease expiry, got %v", err)
+		}
+
+		fresh := mustClaim(t, ctx, store, key, longLease)
+		if !fresh.Acquired || fresh.Result != nil {
+			t.Fatalf("expected claim without result to be acquired, got %+v", fresh)
+		}
+		if fresh.Token <= stale.Token {
+			t.Fatalf("expected token greater than %d, got %d", stale.Token, fresh.Token)
+		}
+	})
+
+	t.Run("CompletedExpires", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+
+		first := mustClaim(t, ctx, store, key, longLease)
+		if err := store.Complete(ctx, key, first.Token, []byte("done"), shortLease); err != nil {
+			t.Fatalf("failed to complete: %v", err)
+		}
+		time.Sleep(expiryWait)
+
+		second := mustClaim(t, ctx, store, key, longLease)
+		if !second.Acquired {
+			t.Fatalf("expected claim to be acquired after ttl, got %+v", second)
+		}
+		if second.Result != nil {
+			t.Fatalf("expected no result for new claim, got %q", second.Result)
+		}
+		if second.Token <= first.Token {
+			t.Fatalf("expected token greater than %d, got %d", first.Token, second.Token)
+		}
+	})
+
+	t.Run("ConcurrentClaims", func(t *testing.T) {
+		store, ctx, key := setup(t, newStore)
+		const workers = 16
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			acquired int
+			errs     []error
+		)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				claim, err := store.Claim(ctx, key, longLease)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+					return
+				}
+				if claim.Acquired {
+					acquired++
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(errs) > 0 {
+			t.Fatalf("failed to claim: %v", errs[0])
+		}
+		if acquired != 1 {
+			t.Fatalf("expected exactly one acquired claim, got %d", acquired)
+		}
+	})
+}
+
+// setup 저장소와 컨텍스트, 다른 테스트와 겹치지 않는 키 생성
+func setup(t *testing.T, newStore func(t *testing.T) idempotency.Store) (idempotency.Store, context.Context, string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return newStore(t), ctx, fmt.Sprintf("conformance:%s:%s", t.Name(), uuid.NewString())
+}
+
+func mustClaim(t *testing.T, ctx context.Context, store idempotency.Store, key string, lease time.Duration) *idempotency.Claim {
+	t.Helper()
+
+	claim, err := store.Claim(ctx, key, lease)
+	if err != nil {
+		t.Fatalf("failed to claim: %v", err)
+	}
+	return claim
+}
@@ -0,0 +1,133 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore 프로세스 내 메모리 기반 멱등성 저장소 (테스트, 단일 프로세스 실행용)
+//
+// 만료된 항목은 조회 시 없는 것으로 취급하고, StartSweeper 가 주기적으로 메모리에서 제거한다.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastToken int64
+}
+
+// memoryEntry 키 상태 (expiresAt 은 IN_PROGRESS 면 lease 만료, COMPLETED 면 보관 만료 시각)
+type memoryEntry struct {
+	state     State
+	token     int64
+	result    []byte
+	expiresAt time.Time
+}
+
+// NewMemoryStore 메모리 기반 멱등성 저장소 생성
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+// Claim 멱등성 키 점유
+func (s *MemoryStore) Claim(ctx context.Context, key string, lease time.Duration) (*Claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		return &Claim{
+			Key:    key,
+			State:  entry.state,
+			Token:  entry.token,
+			Result: entry.copyResult(),
+		}, nil
+	}
+
+	// 키가 해제되거나 제거되어도 token 이 줄지 않도록 저장소 전체 카운터 사용
+	s.lastToken++
+	token := s.lastToken
+
+	s.entries[key] = &memoryEntry{
+		state:     StateInProgress,
+		token:     token,
+		expiresAt: now.Add(lease),
+	}
+	return &Claim{Key: key, State: StateInProgress, Acquired: true, Token: token}, nil
+}
+
+// Complete 처리 완료 표시
+func (s *MemoryStore) Complete(ctx context.Context, key string, token int64, result []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.state != StateInProgress || entry.token != token || !time.Now().Before(entry.expiresAt) {
+		return ErrClaimLost
+	}
+
+	entry.state = StateCompleted
+	entry.result = append([]byte(nil), result...)
+	entry.expiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+// Release 점유 해제
+func (s *MemoryStore) Release(ctx context.Context, key string, token int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.state == StateInProgress && entry.token == token {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// Sweep 만료된 항목을 제거하고 개수 반환 (lease 가 지난 IN_PROGRESS 항목도 제거)
+func (s *MemoryStore) Sweep() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// StartSweeper 주기적으로 만료된 항목 제거 (ctx 취소 시 종료)
+func (s *MemoryStore) StartSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
+
+// Len 보관 중인 항목 수 (만료되었지만 아직 제거되지 않은 항목 포함)
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// copyResult 보관된 결과의 복사본 (COMPLETED 가 아니면 nil)
+func (e *memoryEntry) copyResult() []byte {
+	if e.state != StateCompleted || e.result == nil {
+		return nil
+	}
+	return append([]byte(nil), e.result...)
+}
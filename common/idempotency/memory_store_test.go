@@ -0,0 +1,50 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency/idempotencytest"
+)
+
+func TestMemoryStoreConformance(t *testing.T) {
+	idempotencytest.RunStoreConformance(t, func(t *testing.T) idempotency.Store {
+		return idempotency.NewMemoryStore()
+	})
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewMemoryStore()
+
+	expired, err := store.Claim(ctx, "expired", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to claim: %v", err)
+	}
+	if _, err := store.Claim(ctx, "active", time.Minute); err != nil {
+		t.Fatalf("failed to claim: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if removed := store.Sweep(); removed != 1 {
+		t.Fatalf("expected 1 swept entry, got %d", removed)
+	}
+	if n := store.Len(); n != 1 {
+		t.Fatalf("expected 1 remaining entry, got %d", n)
+	}
+
+	// 제거된 점유는 완료할 수 없고, 다시 점유하면 더 큰 token 을 받는다
+	if err := store.Complete(ctx, "expired", expired.Token, nil, time.Minute); !errors.Is(err, idempotency.ErrClaimLost) {
+		t.Fatalf("expected ErrClaimLost after sweep, got %v", err)
+	}
+	claim, err := store.Claim(ctx, "expired", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to claim: %v", err)
+	}
+	if !claim.Acquired || claim.Token <= expired.Token {
+		t.Fatalf("expected acquired claim with token greater than %d, got %+v", expired.Token, claim)
+	}
+}
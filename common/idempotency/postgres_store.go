@@ -0,0 +1,204 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tokenExpr DB 시각 기반 fencing token (마이크로초, 이전 점유의 token 보다 작으면 그 다음 값)
+const tokenExpr = `(EXTRACT(EPOCH FROM clock_timestamp()) * 1000000)::BIGINT`
+
+// PostgresStore PostgreSQL processed_events 테이블 기반 멱등성 저장소
+//
+// Store 로 사용할 수 있고, RecordTx 와 Transactional 로 비즈니스 트랜잭션 안에서 처리 기록을 남기면
+// 중복 체크와 부수 효과가 함께 커밋되어 커밋 직후 장애가 나도 다시 처리하지 않는다.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore PostgreSQL 기반 멱등성 저장소 생성
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Claim 멱등성 키 점유 (없거나 만료된 행만 IN_PROGRESS 로 갱신)
+func (s *PostgresStore) Claim(ctx context.Context, key string, lease time.Duration) (*Claim, error) {
+	query := `
+		INSERT INTO processed_events (idempotency_key, state, token, expires_at)
+		VALUES ($1, $2, ` + tokenExpr + `, NOW() + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (idempotency_key) DO UPDATE
+		SET state = EXCLUDED.state,
+		    token = GREATEST(EXCLUDED.token, processed_events.token + 1),
+		    result = NULL,
+		    expires_at = EXCLUDED.expires_at,
+		    updated_at = NOW()
+		WHERE processed_events.expires_at < NOW()
+		RETURNING token
+	`
+
+	claim := &Claim{Key: key, State: StateInProgress}
+	err := s.db.QueryRowContext(ctx, query, key, StateInProgress, lease.Milliseconds()).Scan(&claim.Token)
+	if err == nil {
+		claim.Acquired = true
+		return claim, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	// 유효한 점유나 완료 기록이 있음
+	var state string
+	err = s.db.QueryRowContext(ctx,
+		`SELECT state, token, result FROM processed_events WHERE idempotency_key = $1`,
+		key,
+	).Scan(&state, &claim.Token, &claim.Result)
+	if err == sql.ErrNoRows {
+		// 조회 사이에 정리된 경우 다음 시도에서 다시 점유
+		return claim, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	claim.State = State(state)
+	return claim, nil
+}
+
+// Complete 처리 완료 표시
+func (s *PostgresStore) Complete(ctx context.Context, key string, token int64, result []byte, ttl time.Duration) error {
+	return s.complete(ctx, s.db, key, token, result, ttl)
+}
+
+// CompleteTx 비즈니스 트랜잭션 안에서 처리 완료 표시 (Claim 으로 점유한 뒤 결과를 함께 커밋할 때)
+func (s *PostgresStore) CompleteTx(ctx context.Context, tx *sql.Tx, key string, token int64, result []byte, ttl time.Duration) error {
+	return s.complete(ctx, tx, key, token, result, ttl)
+}
+
+func (s *PostgresStore) complete(ctx context.Context, exec execer, key string, token int64, result []byte, ttl time.Duration) error {
+	query := `
+		UPDATE processed_events
+		SET state = $3, result = $4, expires_at = NOW() + $5 * INTERVAL '1 millisecond', updated_at = NOW()
+		WHERE idempotency_key = $1 AND token = $2 AND state = $6 AND expires_at >= NOW()
+	`
+
+	res, err := exec.ExecContext(ctx, query, key, token, StateCompleted, result, ttl.Milliseconds(), StateInProgress)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return ErrClaimLost
+	}
+	return nil
+}
+
+// Release 점유 해제
+func (s *PostgresStore) Release(ctx context.Context, key string, token int64) error {
+	query := `
+		DELETE FROM processed_events
+		WHERE idempotency_key = $1 AND token = $2 AND state = $3
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, key, token, StateInProgress); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// RecordTx 비즈니스 트랜잭션 안에서 키를 COMPLETED 로 기록하고, 이미 처리되었거나 처리 중이면 false 반환
+//
+// 같은 키로 동시에 기록하면 뒤 트랜잭션은 앞 트랜잭션이 끝날 때까지 기다렸다가, 앞 트랜잭션이 커밋되면
+// false 를, 롤백되면 true 를 받는다. 따라서 트랜잭션이 커밋된 경우에만 처리된 것으로 남는다.
+func (s *PostgresStore) RecordTx(ctx context.Context, tx *sql.Tx, key string, result []byte, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO processed_events (idempotency_key, state, token, result, expires_at)
+		VALUES ($1, $2, ` + tokenExpr + `, $3, NOW() + $4 * INTERVAL '1 millisecond')
+		ON CONFLICT (idempotency_key) DO UPDATE
+		SET state = EXCLUDED.state,
+		    token = GREATEST(EXCLUDED.token, processed_events.token + 1),
+		    result = EXCLUDED.result,
+		    expires_at = EXCLUDED.expires_at,
+		    updated_at = NOW()
+		WHERE processed_events.expires_at < NOW()
+		RETURNING token
+	`
+
+	var token int64
+	err := tx.QueryRowContext(ctx, query, key, StateCompleted, result, ttl.Milliseconds()).Scan(&token)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record idempotency key: %w", err)
+	}
+	return true, nil
+}
+
+// DeleteExpired 만료된 기록을 최대 limit 건 삭제하고 개수 반환
+func (s *PostgresStore) DeleteExpired(ctx context.Context, limit int) (int64, error) {
+	query := `
+		DELETE FROM processed_events
+		WHERE idempotency_key IN (
+			SELECT idempotency_key
+			FROM processed_events
+			WHERE expires_at < NOW()
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	res, err := s.db.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// StartSweeper 주기적으로 만료된 기록 삭제 (ctx 취소 시 종료)
+func (s *PostgresStore) StartSweeper(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	const batchSize = 1000
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var total int64
+		for ctx.Err() == nil {
+			n, err := s.DeleteExpired(ctx, batchSize)
+			total += n
+			if err != nil {
+				logger.Error("failed to sweep processed events", zap.Error(err))
+				break
+			}
+			if n < batchSize {
+				break
+			}
+		}
+		if total > 0 {
+			logger.Info("expired processed events swept", zap.Int64("count", total))
+		}
+	}
+}
+
+// execer *sql.DB 와 *sql.Tx 공통 실행 인터페이스
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
@@ -0,0 +1,143 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenRetention 논리적으로 만료된 키를 fencing token 카운터 보존을 위해 더 남겨두는 시간
+//
+// 키가 물리적으로 사라지면 카운터가 처음부터 다시 시작하므로, 이 시간보다 오래 멈춰 있던 처리자는 막지 못한다.
+const tokenRetention = time.Hour
+
+// nowMillisLua Redis 서버 시각 (밀리초, lease 와 보관 만료 판단에만 사용)
+const nowMillisLua = `
+local now = redis.call('TIME')
+local nowMs = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000)
+`
+
+// claimScript 키가 없거나 deadline 이 지났으면 IN_PROGRESS 로 점유
+//
+// token 은 같은 해시의 카운터(HINCRBY)라 서버 시각이 되돌아가거나 페일오버로 복제본이 승격되어도 줄지 않는다.
+// deadline 필드가 없는 키는 이전 형식이므로 키 TTL 이 남아 있는 동안 유효한 것으로 본다.
+// 문자열 키는 이전 Reserve(SETNX)가 남긴 것이므로 TTL 이 끝날 때까지 COMPLETED 로 보고 점유하지 않는다.
+var claimScript = redis.NewScript(nowMillisLua + `
+if redis.call('TYPE', KEYS[1])['ok'] == 'string' then
+	return {'COMPLETED', '0', '', 0}
+end
+local state = redis.call('HGET', KEYS[1], 'state')
+local deadline = redis.call('HGET', KEYS[1], 'deadline')
+if state and (not deadline or tonumber(deadline) > nowMs) then
+	return {state, redis.call('HGET', KEYS[1], 'token'), redis.call('HGET', KEYS[1], 'result') or '', 0}
+end
+local token = redis.call('HINCRBY', KEYS[1], 'token', 1)
+redis.call('HSET', KEYS[1], 'state', 'IN_PROGRESS', 'deadline', string.format('%.0f', nowMs + tonumber(ARGV[1])))
+redis.call('HDEL', KEYS[1], 'result')
+redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[1]) + tonumber(ARGV[2]))
+return {'IN_PROGRESS', string.format('%.0f', token), '', 1}
+`)
+
+// completeScript token 이 일치하고 lease 가 남은 IN_PROGRESS 점유만 COMPLETED 로 전환
+var completeScript = redis.NewScript(nowMillisLua + `
+if redis.call('TYPE', KEYS[1])['ok'] ~= 'hash' then
+	return 0
+end
+if redis.call('HGET', KEYS[1], 'state') ~= 'IN_PROGRESS' or redis.call('HGET', KEYS[1], 'token') ~= ARGV[1]
+	or tonumber(redis.call('HGET', KEYS[1], 'deadline') or '0') <= nowMs then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'state', 'COMPLETED', 'result', ARGV[2], 'deadline', string.format('%.0f', nowMs + tonumber(ARGV[3])))
+redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[3]) + tonumber(ARGV[4]))
+return 1
+`)
+
+// releaseScript token 이 일치하는 IN_PROGRESS 점유를 만료 처리 (카운터를 보존하기 위해 키는 지우지 않음)
+var releaseScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1])['ok'] ~= 'hash' then
+	return 0
+end
+if redis.call('HGET', KEYS[1], 'state') == 'IN_PROGRESS' and redis.call('HGET', KEYS[1], 'token') == ARGV[1] then
+	redis.call('HSET', KEYS[1], 'deadline', 0)
+	return 1
+end
+return 0
+`)
+
+// RedisStore Redis 기반 멱등성 저장소
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore Redis 기반 멱등성 저장소 생성
+//
+// 단일 노드(*redis.Client), Cluster(*redis.ClusterClient), Sentinel(redis.NewFailoverClient) 모두 사용할 수 있다.
+// 스크립트는 키 하나만 다루므로 Cluster 에서도 슬롯이 갈리지 않는다.
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Claim 멱등성 키 점유
+func (s *RedisStore) Claim(ctx context.Context, key string, lease time.Duration) (*Claim, error) {
+	reply, err := claimScript.Run(ctx, s.client, []string{s.getFullKey(key)},
+		lease.Milliseconds(), tokenRetention.Milliseconds()).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if len(reply) != 4 {
+		return nil, fmt.Errorf("failed to claim idempotency key: unexpected reply %v", reply)
+	}
+
+	state, _ := reply[0].(string)
+	token, err := strconv.ParseInt(fmt.Sprint(reply[1]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fencing token: %w", err)
+	}
+	acquired, _ := reply[3].(int64)
+
+	claim := &Claim{
+		Key:      key,
+		State:    State(state),
+		Acquired: acquired == 1,
+		Token:    token,
+	}
+	if result, _ := reply[2].(string); claim.State == StateCompleted && result != "" {
+		claim.Result = []byte(result)
+	}
+	return claim, nil
+}
+
+// Complete 처리 완료 표시
+func (s *RedisStore) Complete(ctx context.Context, key string, token int64, result []byte, ttl time.Duration) error {
+	ok, err := completeScript.Run(ctx, s.client, []string{s.getFullKey(key)},
+		strconv.FormatInt(token, 10), result, ttl.Milliseconds(), tokenRetention.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	if ok == 0 {
+		return ErrClaimLost
+	}
+	return nil
+}
+
+// Release 점유 해제
+func (s *RedisStore) Release(ctx context.Context, key string, token int64) error {
+	err := releaseScript.Run(ctx, s.client, []string{s.getFullKey(key)}, strconv.FormatInt(token, 10)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) getFullKey(key string) string {
+	return fmt.Sprintf("%s:%s", s.prefix, key)
+}
@@ -0,0 +1,71 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency/idempotencytest"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisStoreConformance(t *testing.T) {
+	client := newRedisClient(t)
+
+	prefix := "idempotency-test:" + uuid.NewString()
+	idempotencytest.RunStoreConformance(t, func(t *testing.T) idempotency.Store {
+		return idempotency.NewRedisStore(client, prefix)
+	})
+}
+
+func TestRedisStoreLegacyReservation(t *testing.T) {
+	client := newRedisClient(t)
+	ctx := context.Background()
+
+	// 이전 Reserve 가 SETNX 로 남긴 문자열 키
+	prefix := "idempotency-test:" + uuid.NewString()
+	if err := client.Set(ctx, prefix+":event-1", "1", time.Minute).Err(); err != nil {
+		t.Fatalf("failed to write legacy key: %v", err)
+	}
+
+	store := idempotency.NewRedisStore(client, prefix)
+	claim, err := store.Claim(ctx, "event-1", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to claim legacy key: %v", err)
+	}
+	if claim.Acquired || claim.State != idempotency.StateCompleted {
+		t.Fatalf("expected legacy key to be treated as COMPLETED, got %+v", claim)
+	}
+	if err := store.Complete(ctx, "event-1", 1, nil, time.Minute); !errors.Is(err, idempotency.ErrClaimLost) {
+		t.Fatalf("expected ErrClaimLost for legacy key, got %v", err)
+	}
+	if err := store.Release(ctx, "event-1", 1); err != nil {
+		t.Fatalf("failed to release legacy key: %v", err)
+	}
+}
+
+// newRedisClient REDIS_ADDR (쉼표로 여러 개면 Cluster) 와 REDIS_MASTER_NAME (Sentinel) 으로 대상 Redis 지정
+func newRedisClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+
+	client := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:      strings.Split(addr, ","),
+		MasterName: os.Getenv("REDIS_MASTER_NAME"),
+	})
+	t.Cleanup(func() { client.Close() })
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	return client
+}
@@ -2,66 +2,66 @@ package idempotency
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"time"
+)
+
+// State 멱등성 키 상태
+type State string
 
-	"github.com/redis/go-redis/v9"
+const (
+	// StateInProgress 처리자가 lease 동안 점유 중 (lease 가 지나면 다른 처리자가 넘겨받음)
+	StateInProgress State = "IN_PROGRESS"
+	// StateCompleted 처리 완료 (결과를 보관 기간 동안 재사용)
+	StateCompleted State = "COMPLETED"
 )
 
-// Store 멱등성 키 저장소 인터페이스
-type Store interface {
-	// Reserve 멱등성 키를 예약 (이미 존재하면 false 반환)
-	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
-	// IsProcessed 이미 처리된 키인지 확인
-	IsProcessed(ctx context.Context, key string) (bool, error)
-	// Release 멱등성 키 해제
-	Release(ctx context.Context, key string) error
-}
+// ErrInProgress 다른 처리자가 같은 키를 처리 중 (잠시 후 다시 시도)
+var ErrInProgress = errors.New("idempotency key is being processed")
 
-// RedisStore Redis 기반 멱등성 저장소
-type RedisStore struct {
-	client *redis.Client
-	prefix string
-}
+// ErrClaimLost 점유의 lease 가 지났거나, 다른 처리자가 넘겨받았거나, 이미 완료됨
+var ErrClaimLost = errors.New("idempotency claim lost")
 
-// NewRedisStore Redis 기반 멱등성 저장소 생성
-func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
-	return &RedisStore{
-		client: client,
-		prefix: prefix,
-	}
+// Claim 점유 시도 결과
+type Claim struct {
+	Key string
+	// State 시도 후 키 상태
+	State State
+	// Acquired 이번 시도로 점유했는지 여부 (true 면 처리 후 Complete 또는 Release 해야 함)
+	Acquired bool
+	// Token fencing token (점유할 때마다 키별로 증가, 만료 후 넘겨받은 처리자가 더 큰 값을 가짐)
+	Token int64
+	// Result COMPLETED 일 때 저장된 결과 (없으면 nil)
+	Result []byte
 }
 
-// Reserve 멱등성 키 예약
-func (s *RedisStore) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
-	fullKey := s.getFullKey(key)
-	result, err := s.client.SetNX(ctx, fullKey, "1", ttl).Result()
-	if err != nil {
-		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
-	}
-	return result, nil
+// Store 멱등성 키 저장소 인터페이스
+//
+// 처리 흐름은 Claim → 처리 → Complete(성공) 또는 Release(실패) 이다. Claim 은 원자적이므로 같은 키로 동시에
+// 들어온 요청 중 하나만 처리한다. 처리자가 죽어 lease 가 지나면 다음 Claim 이 더 큰 token 으로 넘겨받고,
+// 이전 처리자의 Complete 는 ErrClaimLost 로 거부된다. 넘겨받은 처리자가 없어도 lease 가 지난 점유는 완료할 수 없다.
+//
+// 모든 구현은 idempotencytest.RunStoreConformance 를 통과해야 한다.
+type Store interface {
+	// Claim 키가 없거나 lease 가 만료되었으면 lease 동안 IN_PROGRESS 로 점유
+	// (다른 처리자가 점유 중이거나 COMPLETED 면 Acquired=false 와 현재 상태 반환)
+	Claim(ctx context.Context, key string, lease time.Duration) (*Claim, error)
+	// Complete 점유한 키를 COMPLETED 로 바꾸고 결과를 ttl 동안 보관
+	// (token 이 현재 점유와 다르거나 lease 가 지났으면 ErrClaimLost)
+	Complete(ctx context.Context, key string, token int64, result []byte, ttl time.Duration) error
+	// Release 처리 실패 시 점유 해제 (token 이 현재 점유와 다르면 아무것도 하지 않음)
+	Release(ctx context.Context, key string, token int64) error
 }
 
-// IsProcessed 이미 처리된 키인지 확인
-func (s *RedisStore) IsProcessed(ctx context.Context, key string) (bool, error) {
-	fullKey := s.getFullKey(key)
-	exists, err := s.client.Exists(ctx, fullKey).Result()
-	if err != nil {
-		return false, fmt.Errorf("failed to check idempotency key: %w", err)
-	}
-	return exists > 0, nil
-}
+type claimContextKey struct{}
 
-// Release 멱등성 키 해제
-func (s *RedisStore) Release(ctx context.Context, key string) error {
-	fullKey := s.getFullKey(key)
-	_, err := s.client.Del(ctx, fullKey).Result()
-	if err != nil {
-		return fmt.Errorf("failed to release idempotency key: %w", err)
-	}
-	return nil
+// WithClaim 점유 정보를 컨텍스트에 담음 (핸들러가 fencing token 을 저장소 쓰기에 사용할 수 있도록)
+func WithClaim(ctx context.Context, claim *Claim) context.Context {
+	return context.WithValue(ctx, claimContextKey{}, claim)
 }
 
-func (s *RedisStore) getFullKey(key string) string {
-	return fmt.Sprintf("%s:%s", s.prefix, key)
+// ClaimFromContext 컨텍스트의 점유 정보 (없으면 nil)
+func ClaimFromContext(ctx context.Context) *Claim {
+	claim, _ := ctx.Value(claimContextKey{}).(*Claim)
+	return claim
 }
@@ -0,0 +1,50 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Identified 이벤트 ID 를 가진 메시지 (events.Event 가 만족)
+type Identified interface {
+	GetEventID() string
+}
+
+// TxHandler 비즈니스 트랜잭션 안에서 실행되는 이벤트 핸들러 (커밋과 롤백은 호출자가 담당)
+type TxHandler[E Identified] func(ctx context.Context, tx *sql.Tx, evt E) error
+
+// Transactional 트랜잭션을 열어 이벤트 ID 를 processed_events 에 기록하고 핸들러를 실행한 뒤 함께 커밋
+//
+// 이미 처리된 이벤트면 핸들러를 실행하지 않고, 핸들러가 실패하면 처리 기록까지 롤백해 재전달 시 다시 처리한다.
+//
+//	messaging.On[events.OrderCreatedEvent](router,
+//	    idempotency.Transactional(processedStore, 24*time.Hour, paymentService.HandleOrderCreated))
+func Transactional[E Identified](store *PostgresStore, ttl time.Duration, handler TxHandler[E]) func(ctx context.Context, evt E) error {
+	return func(ctx context.Context, evt E) error {
+		tx, err := store.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		recorded, err := store.RecordTx(ctx, tx, evt.GetEventID(), nil, ttl)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			// 이미 처리됨
+			return nil
+		}
+
+		if err := handler(ctx, tx, evt); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	}
+}
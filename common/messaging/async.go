@@ -0,0 +1,256 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// DeliveryResult 비동기 발행 결과
+type DeliveryResult struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
+// DeliveryCallback 브로커 응답(ack) 수신 시 호출되는 콜백
+//
+// 모든 메시지의 콜백이 ack 수신 고루틴 하나에서 차례로 실행되므로, 콜백이 오래 걸리면 ack 처리가 밀리고
+// 결국 발행까지 막힌다. DB 쓰기처럼 느린 작업은 결과만 모아 두고 콜백 밖에서 처리한다.
+type DeliveryCallback func(result DeliveryResult)
+
+// AsyncPublishing 비동기 발행을 지원하는 Publisher
+type AsyncPublishing interface {
+	Publisher
+	PublishAsync(ctx context.Context, topic string, key string, headers Headers, event interface{}, callback DeliveryCallback) error
+}
+
+// AsyncPublisherConfig 비동기 발행자 설정
+type AsyncPublisherConfig struct {
+	ServiceName string
+	Codec       Codec
+	// Linger 배치를 모으기 위해 기다리는 최대 시간 (linger.ms)
+	Linger time.Duration
+	// BatchSize 한 번에 전송할 최대 메시지 수
+	BatchSize int
+	// Compression none, gzip, snappy, lz4, zstd
+	Compression string
+	// Metrics 발행 결과 계측 (nil 이면 기록하지 않음)
+	Metrics Metrics
+}
+
+// DefaultAsyncPublisherConfig 기본 비동기 발행자 설정
+func DefaultAsyncPublisherConfig(serviceName string) AsyncPublisherConfig {
+	return AsyncPublisherConfig{
+		ServiceName: serviceName,
+		Codec:       JSONCodec{},
+		Linger:      10 * time.Millisecond,
+		BatchSize:   100,
+		Compression: "snappy",
+	}
+}
+
+// AsyncPublisher sarama.AsyncProducer 기반 배치 발행자
+type AsyncPublisher struct {
+	producer    sarama.AsyncProducer
+	logger      *zap.Logger
+	serviceName string
+	codec       Codec
+	metrics     Metrics
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var _ AsyncPublishing = (*AsyncPublisher)(nil)
+
+// NewAsyncPublisher 비동기 배치 발행자 생성
+func NewAsyncPublisher(brokers []string, cfg AsyncPublisherConfig, logger *zap.Logger) (*AsyncPublisher, error) {
+	compression, err := parseCompression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 5
+	config.Producer.Idempotent = true
+	config.Net.MaxOpenRequests = 1
+	config.Producer.Compression = compression
+	config.Producer.Flush.Frequency = cfg.Linger
+	config.Producer.Flush.Messages = cfg.BatchSize
+	config.Producer.Flush.MaxMessages = cfg.BatchSize
+
+	producer, err := sarama.NewAsyncProducer(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create async kafka producer: %w", err)
+	}
+
+	codec := cfg.Codec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = NopMetrics{}
+	}
+
+	p := &AsyncPublisher{
+		producer:    producer,
+		logger:      logger,
+		serviceName: cfg.ServiceName,
+		codec:       codec,
+		metrics:     metrics,
+	}
+
+	p.wg.Add(2)
+	go p.handleSuccesses()
+	go p.handleErrors()
+
+	return p, nil
+}
+
+// parseCompression 압축 코덱 이름 변환
+func parseCompression(name string) (sarama.CompressionCodec, error) {
+	switch name {
+	case "", "none":
+		return sarama.CompressionNone, nil
+	case "gzip":
+		return sarama.CompressionGZIP, nil
+	case "snappy":
+		return sarama.CompressionSnappy, nil
+	case "lz4":
+		return sarama.CompressionLZ4, nil
+	case "zstd":
+		return sarama.CompressionZSTD, nil
+	}
+	return sarama.CompressionNone, fmt.Errorf("unsupported compression: %s", name)
+}
+
+// PublishAsync 메시지를 배치 큐에 넣고 즉시 반환 (ack 수신 시 callback 호출)
+func (p *AsyncPublisher) PublishAsync(
+	ctx context.Context,
+	topic string,
+	key string,
+	headers Headers,
+	event interface{},
+	callback DeliveryCallback,
+) error {
+	payload, contentType, err := encodePayload(p.codec, event)
+	if err != nil {
+		return err
+	}
+
+	headers = headers.clone()
+	headers[HeaderContentType] = contentType
+	if p.serviceName != "" && headers.Get(HeaderProducerService) == "" {
+		headers[HeaderProducerService] = p.serviceName
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic:    topic,
+		Key:      sarama.StringEncoder(key),
+		Value:    sarama.ByteEncoder(payload),
+		Headers:  headers.toRecordHeaders(),
+		Metadata: callback,
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return fmt.Errorf("publisher is closed")
+	}
+
+	select {
+	case p.producer.Input() <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publish 이벤트 발행 (ack 수신까지 대기)
+func (p *AsyncPublisher) Publish(ctx context.Context, topic string, key string, event interface{}) error {
+	return p.PublishWithHeaders(ctx, topic, key, eventHeaders(event), event)
+}
+
+// PublishWithHeaders 헤더와 함께 이벤트 발행 (ack 수신까지 대기)
+func (p *AsyncPublisher) PublishWithHeaders(ctx context.Context, topic string, key string, headers Headers, event interface{}) error {
+	done := make(chan error, 1)
+	err := p.PublishAsync(ctx, topic, key, headers, event, func(result DeliveryResult) {
+		done <- result.Err
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 큐에 남은 메시지를 모두 전송한 뒤 종료
+func (p *AsyncPublisher) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.producer.AsyncClose()
+	p.wg.Wait()
+	return nil
+}
+
+func (p *AsyncPublisher) handleSuccesses() {
+	defer p.wg.Done()
+	for msg := range p.producer.Successes() {
+		p.metrics.MessagePublished(msg.Topic, nil)
+		p.logger.Debug("message acknowledged",
+			zap.String("topic", msg.Topic),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset))
+
+		if callback, ok := msg.Metadata.(DeliveryCallback); ok && callback != nil {
+			callback(DeliveryResult{
+				Topic:     msg.Topic,
+				Partition: msg.Partition,
+				Offset:    msg.Offset,
+			})
+		}
+	}
+}
+
+func (p *AsyncPublisher) handleErrors() {
+	defer p.wg.Done()
+	for perr := range p.producer.Errors() {
+		p.metrics.MessagePublished(perr.Msg.Topic, perr.Err)
+		p.logger.Error("failed to send message",
+			zap.Error(perr.Err),
+			zap.String("topic", perr.Msg.Topic))
+
+		if callback, ok := perr.Msg.Metadata.(DeliveryCallback); ok && callback != nil {
+			callback(DeliveryResult{
+				Topic:     perr.Msg.Topic,
+				Partition: perr.Msg.Partition,
+				Offset:    perr.Msg.Offset,
+				Err:       fmt.Errorf("failed to send message: %w", perr.Err),
+			})
+		}
+	}
+}
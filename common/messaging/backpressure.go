@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BackpressureError 핸들러가 다운스트림 과부하를 알리는 에러
+//
+// 컨슈머는 이 에러를 받으면 메시지를 재시도 토픽/DLQ로 보내지 않고, 해당 파티션의 수신을
+// 멈춘 뒤 Delay 가 지나면 같은 메시지부터 다시 처리한다.
+type BackpressureError struct {
+	Delay time.Duration
+	Err   error
+}
+
+func (e *BackpressureError) Error() string {
+	return fmt.Sprintf("backpressure for %s: %v", e.Delay, e.Err)
+}
+
+func (e *BackpressureError) Unwrap() error {
+	return e.Err
+}
+
+// Backpressure delay 동안 파티션 소비를 멈추도록 요청하는 에러 생성
+func Backpressure(delay time.Duration, cause error) error {
+	return &BackpressureError{Delay: delay, Err: cause}
+}
+
+// backpressureDelay 에러가 back-pressure 요청이면 대기 시간 반환
+func backpressureDelay(err error) (time.Duration, bool) {
+	var bpErr *BackpressureError
+	if !stderrors.As(err, &bpErr) {
+		return 0, false
+	}
+	if bpErr.Delay <= 0 {
+		return time.Second, true
+	}
+	return bpErr.Delay, true
+}
+
+// RateLimit 토픽별 초당 처리량 제한
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// rateLimiter 토큰 버킷 기반 처리량 제한
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   limit.PerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait 토큰을 얻을 때까지 대기 (컨텍스트가 취소되면 에러)
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := waitUntil(ctx, time.Now().Add(delay)); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve 토큰이 있으면 소비하고 0을, 없으면 다음 토큰까지 남은 시간을 반환
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// partitionPauser 파티션 pause/resume 참조 카운트
+//
+// 키 단위 병렬 처리에서는 같은 파티션의 여러 워커가 동시에 back-pressure 를 요청할 수 있으므로
+// 마지막 워커가 재개할 때만 파티션을 resume 한다.
+type partitionPauser struct {
+	mu     sync.Mutex
+	counts map[partitionKey]int
+	pause  func(topic string, partition int32)
+	resume func(topic string, partition int32)
+}
+
+func newPartitionPauser(pause, resume func(topic string, partition int32)) *partitionPauser {
+	return &partitionPauser{
+		counts: make(map[partitionKey]int),
+		pause:  pause,
+		resume: resume,
+	}
+}
+
+func (p *partitionPauser) acquire(topic string, partition int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := partitionKey{topic, partition}
+	if p.counts[key] == 0 {
+		p.pause(topic, partition)
+	}
+	p.counts[key]++
+}
+
+func (p *partitionPauser) release(topic string, partition int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := partitionKey{topic, partition}
+	p.counts[key]--
+	if p.counts[key] <= 0 {
+		delete(p.counts, key)
+		p.resume(topic, partition)
+	}
+}
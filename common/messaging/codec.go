@@ -0,0 +1,162 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+)
+
+// HeaderContentType 메시지 직렬화 형식 헤더 키
+const HeaderContentType = "content-type"
+
+// 지원하는 Content-Type
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeGob  = "application/x-gob"
+)
+
+// Codec 메시지 직렬화 인터페이스
+//
+// Protobuf, Avro 등은 이 인터페이스를 구현해 CodecRegistry에 등록한다.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec JSON 직렬화 (기본값)
+type JSONCodec struct{}
+
+// ContentType Content-Type 반환
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+// Marshal JSON 인코딩
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal JSON 디코딩
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// GobCodec Go gob 바이너리 직렬화 (Go 서비스 간 통신 전용)
+type GobCodec struct{}
+
+// ContentType Content-Type 반환
+func (GobCodec) ContentType() string { return ContentTypeGob }
+
+// Marshal gob 인코딩
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal gob 디코딩
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Encoded 이미 직렬화된 페이로드 (발행자의 코덱을 거치지 않고 ContentType 과 함께 그대로 발행)
+//
+// Outbox 처럼 저장해 둔 바이트를 다시 발행할 때 사용한다. 발행자 코덱이 gob 이어도 JSON 으로 저장된
+// 페이로드를 다시 인코딩하지 않으므로 컨슈머는 원래 형식으로 디코딩한다.
+type Encoded struct {
+	ContentType string
+	Data        []byte
+}
+
+// encodePayload 이벤트를 codec 으로 직렬화하고 Content-Type 반환 (Encoded 면 그대로 사용)
+func encodePayload(codec Codec, event interface{}) ([]byte, string, error) {
+	if encoded, ok := event.(Encoded); ok {
+		return encoded.Data, encoded.ContentType, nil
+	}
+
+	payload, err := codec.Marshal(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return payload, codec.ContentType(), nil
+}
+
+// CodecRegistry Content-Type 별 코덱 저장소
+type CodecRegistry struct {
+	mu           sync.RWMutex
+	codecs       map[string]Codec
+	defaultCodec Codec
+}
+
+// NewCodecRegistry 코덱 저장소 생성 (헤더가 없는 메시지는 defaultCodec 으로 처리)
+func NewCodecRegistry(defaultCodec Codec, codecs ...Codec) *CodecRegistry {
+	r := &CodecRegistry{
+		codecs:       make(map[string]Codec),
+		defaultCodec: defaultCodec,
+	}
+	r.Register(defaultCodec)
+	for _, codec := range codecs {
+		r.Register(codec)
+	}
+	return r
+}
+
+// DefaultCodecs JSON 기본, gob 바이너리를 지원하는 기본 저장소
+var DefaultCodecs = NewCodecRegistry(JSONCodec{}, GobCodec{})
+
+// Register 코덱 등록
+func (r *CodecRegistry) Register(codec Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codecs[codec.ContentType()] = codec
+}
+
+// Lookup Content-Type 에 해당하는 코덱 조회 (빈 값이면 기본 코덱)
+func (r *CodecRegistry) Lookup(contentType string) (Codec, error) {
+	if contentType == "" {
+		return r.defaultCodec, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	codec, ok := r.codecs[contentType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+	}
+	return codec, nil
+}
+
+// Decode 메시지의 content-type 헤더에 맞는 코덱으로 디코딩
+func (r *CodecRegistry) Decode(msg *Message, v interface{}) error {
+	codec, err := r.Lookup(msg.Headers.Get(HeaderContentType))
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeSerializationError, "failed to resolve codec", err)
+	}
+	if err := codec.Unmarshal(msg.Value, v); err != nil {
+		return errors.Wrap(errors.ErrCodeSerializationError,
+			fmt.Sprintf("failed to decode %s message", codec.ContentType()), err)
+	}
+	return nil
+}
+
+// TypedHandler 디코딩된 이벤트를 받는 핸들러
+type TypedHandler[T any] func(ctx context.Context, evt T) error
+
+// Typed 메시지를 T 로 디코딩해 전달하는 MessageHandler 생성 (DefaultCodecs 사용)
+func Typed[T any](handler TypedHandler[T]) MessageHandler {
+	return TypedWith(DefaultCodecs, handler)
+}
+
+// TypedWith 지정한 코덱 저장소로 디코딩하는 MessageHandler 생성
+func TypedWith[T any](codecs *CodecRegistry, handler TypedHandler[T]) MessageHandler {
+	return func(ctx context.Context, msg *Message) error {
+		var evt T
+		if err := codecs.Decode(msg, &evt); err != nil {
+			return err
+		}
+		return handler(ctx, evt)
+	}
+}
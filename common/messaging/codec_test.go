@@ -0,0 +1,103 @@
+package messaging_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"go.uber.org/zap"
+)
+
+func TestCodecRoundTripThroughRouter(t *testing.T) {
+	sent := events.OrderCreatedEvent{
+		BaseEvent: events.BaseEvent{
+			EventID:       "evt-1",
+			EventType:     events.EventOrderCreated,
+			SchemaVersion: 1,
+			OccurredAt:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			CorrelationID: "corr-1",
+		},
+		OrderID:  42,
+		UserID:   7,
+		Amount:   10000,
+		Quantity: 2,
+	}
+
+	stored, err := json.Marshal(sent)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		codec       messaging.Codec
+		payload     interface{}
+		contentType string
+	}{
+		{name: "JSON", codec: messaging.JSONCodec{}, payload: sent, contentType: messaging.ContentTypeJSON},
+		{name: "Gob", codec: messaging.GobCodec{}, payload: sent, contentType: messaging.ContentTypeGob},
+		{
+			// Outbox 처럼 저장된 JSON 을 gob 발행자로 다시 발행해도 이중 인코딩되지 않아야 한다
+			name:        "EncodedJSONWithGobPublisher",
+			codec:       messaging.GobCodec{},
+			payload:     messaging.Encoded{ContentType: messaging.ContentTypeJSON, Data: stored},
+			contentType: messaging.ContentTypeJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := messaging.NewMemoryBroker(1)
+			publisher := broker.NewPublisherWithCodec("test-service", tt.codec)
+
+			headers := messaging.EventHeaders(sent.BaseEvent)
+			if err := publisher.PublishWithHeaders(context.Background(), string(events.EventOrderCreated), "42", headers, tt.payload); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+
+			published := broker.Messages(string(events.EventOrderCreated))
+			if len(published) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(published))
+			}
+			if got := published[0].Headers.Get(messaging.HeaderContentType); got != tt.contentType {
+				t.Fatalf("expected content type %q, got %q", tt.contentType, got)
+			}
+
+			received := make(chan events.OrderCreatedEvent, 1)
+			router := messaging.NewRouter(zap.NewNop())
+			messaging.On[events.OrderCreatedEvent](router, func(ctx context.Context, evt events.OrderCreatedEvent) error {
+				received <- evt
+				return nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			consumer := broker.NewConsumer("test-group", zap.NewNop())
+			done := make(chan error, 1)
+			go func() { done <- consumer.Run(ctx, router.Topics(), router.Handle) }()
+			defer func() {
+				cancel()
+				<-done
+			}()
+
+			select {
+			case got := <-received:
+				if !got.OccurredAt.Equal(sent.OccurredAt) {
+					t.Fatalf("expected occurredAt %v, got %v", sent.OccurredAt, got.OccurredAt)
+				}
+				got.OccurredAt = sent.OccurredAt
+				if got != sent {
+					t.Fatalf("expected %+v, got %+v", sent, got)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+
+			if dlq := broker.Messages(messaging.DLQTopic(string(events.EventOrderCreated))); len(dlq) != 0 {
+				t.Fatalf("expected no dead-lettered messages, got %d", len(dlq))
+			}
+		})
+	}
+}
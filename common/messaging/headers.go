@@ -0,0 +1,119 @@
+package messaging
+
+import (
+	"strconv"
+
+	"github.com/IBM/sarama"
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+)
+
+// 이벤트 메타데이터 헤더 키
+const (
+	HeaderEventType       = "event-type"
+	HeaderEventID         = "event-id"
+	HeaderCorrelationID   = "correlation-id"
+	HeaderCausationID     = "causation-id"
+	HeaderSchemaVersion   = "schema-version"
+	HeaderProducerService = "producer-service"
+)
+
+// Headers 메시지 헤더
+type Headers map[string]string
+
+// Get 헤더 값 조회 (없으면 빈 문자열)
+func (h Headers) Get(key string) string {
+	if h == nil {
+		return ""
+	}
+	return h[key]
+}
+
+// clone 헤더 복사본 생성 (nil 이면 빈 헤더)
+func (h Headers) clone() Headers {
+	copied := make(Headers, len(h)+2)
+	for key, value := range h {
+		copied[key] = value
+	}
+	return copied
+}
+
+// EventHeaders BaseEvent로부터 메타데이터 헤더 생성
+func EventHeaders(evt events.BaseEvent) Headers {
+	headers := Headers{
+		HeaderEventType:     string(evt.EventType),
+		HeaderEventID:       evt.EventID,
+		HeaderCorrelationID: evt.CorrelationID,
+		HeaderSchemaVersion: strconv.Itoa(evt.SchemaVersion),
+	}
+	if evt.CausationID != "" {
+		headers[HeaderCausationID] = evt.CausationID
+	}
+	return headers
+}
+
+// eventHeaders 이벤트 인터페이스를 구현한 값이면 메타데이터 헤더 생성
+func eventHeaders(event interface{}) Headers {
+	evt, ok := event.(events.Event)
+	if !ok {
+		return Headers{}
+	}
+	return EventHeaders(events.BaseEvent{
+		EventID:       evt.GetEventID(),
+		EventType:     evt.GetEventType(),
+		SchemaVersion: evt.GetSchemaVersion(),
+		CorrelationID: evt.GetCorrelationID(),
+		CausationID:   evt.GetCausationID(),
+	})
+}
+
+// toRecordHeaders Kafka 레코드 헤더로 변환
+func (h Headers) toRecordHeaders() []sarama.RecordHeader {
+	records := make([]sarama.RecordHeader, 0, len(h))
+	for key, value := range h {
+		if value == "" {
+			continue
+		}
+		records = append(records, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	return records
+}
+
+// fromRecordHeaders Kafka 레코드 헤더에서 변환
+func fromRecordHeaders(records []*sarama.RecordHeader) Headers {
+	headers := make(Headers, len(records))
+	for _, record := range records {
+		headers[string(record.Key)] = string(record.Value)
+	}
+	return headers
+}
+
+// Position 파티션 안에서 메시지를 식별하는 위치 (Redis Streams 는 엔트리 ID, 그 밖에는 오프셋)
+func (m *Message) Position() string {
+	if id := m.Headers.Get(HeaderStreamID); id != "" {
+		return id
+	}
+	return strconv.FormatInt(m.Offset, 10)
+}
+
+// EventType 이벤트 타입 (헤더가 없으면 토픽 이름 사용)
+func (m *Message) EventType() events.EventType {
+	if eventType := m.Headers.Get(HeaderEventType); eventType != "" {
+		return events.EventType(eventType)
+	}
+	return events.EventType(m.Topic)
+}
+
+// EventID 이벤트 ID 헤더
+func (m *Message) EventID() string {
+	return m.Headers.Get(HeaderEventID)
+}
+
+// CorrelationID 상관관계 ID 헤더
+func (m *Message) CorrelationID() string {
+	return m.Headers.Get(HeaderCorrelationID)
+}
+
+// CausationID 원인 이벤트 ID 헤더
+func (m *Message) CausationID() string {
+	return m.Headers.Get(HeaderCausationID)
+}
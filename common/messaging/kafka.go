@@ -2,9 +2,11 @@ package messaging
 
 import (
 	"context"
-	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 	"go.uber.org/zap"
@@ -13,15 +15,25 @@ import (
 // Publisher 이벤트 발행 인터페이스
 type Publisher interface {
 	Publish(ctx context.Context, topic string, key string, event interface{}) error
+	PublishWithHeaders(ctx context.Context, topic string, key string, headers Headers, event interface{}) error
 	Close() error
 }
 
 // Consumer 이벤트 구독 인터페이스
 type Consumer interface {
 	Subscribe(topics []string, handler MessageHandler) error
+	Run(ctx context.Context, topics []string, handler MessageHandler) error
 	Close() error
 }
 
+// RebalanceHandler 파티션 할당/해제 시점 콜백 (파티션별 상태 초기화/flush 용도)
+type RebalanceHandler interface {
+	// Setup 새 세션에서 파티션이 할당된 직후 호출
+	Setup(ctx context.Context, claims map[string][]int32) error
+	// Cleanup 세션 종료 시 오프셋 커밋 직전에 호출
+	Cleanup(ctx context.Context, claims map[string][]int32) error
+}
+
 // MessageHandler 메시지 핸들러 함수 타입
 type MessageHandler func(ctx context.Context, msg *Message) error
 
@@ -32,16 +44,44 @@ type Message struct {
 	Offset    int64
 	Key       []byte
 	Value     []byte
+	Headers   Headers
 }
 
 // KafkaPublisher Kafka 기반 이벤트 발행자
 type KafkaPublisher struct {
-	producer sarama.SyncProducer
-	logger   *zap.Logger
+	producer    sarama.SyncProducer
+	logger      *zap.Logger
+	serviceName string
+	codec       Codec
+	metrics     Metrics
+}
+
+// PublisherOption Kafka 발행자 옵션
+type PublisherOption func(*KafkaPublisher)
+
+// WithServiceName 발행하는 모든 메시지에 producer-service 헤더 추가
+func WithServiceName(serviceName string) PublisherOption {
+	return func(p *KafkaPublisher) {
+		p.serviceName = serviceName
+	}
+}
+
+// WithCodec 메시지 직렬화 코덱 지정 (기본값 JSON)
+func WithCodec(codec Codec) PublisherOption {
+	return func(p *KafkaPublisher) {
+		p.codec = codec
+	}
+}
+
+// WithPublisherMetrics 발행 결과 계측
+func WithPublisherMetrics(metrics Metrics) PublisherOption {
+	return func(p *KafkaPublisher) {
+		p.metrics = metrics
+	}
 }
 
 // NewKafkaPublisher Kafka 발행자 생성
-func NewKafkaPublisher(brokers []string, logger *zap.Logger) (*KafkaPublisher, error) {
+func NewKafkaPublisher(brokers []string, logger *zap.Logger, opts ...PublisherOption) (*KafkaPublisher, error) {
 	config := sarama.NewConfig()
 	config.Producer.Return.Successes = true
 	config.Producer.RequiredAcks = sarama.WaitForAll
@@ -54,26 +94,46 @@ func NewKafkaPublisher(brokers []string, logger *zap.Logger) (*KafkaPublisher, e
 		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
 	}
 
-	return &KafkaPublisher{
+	publisher := &KafkaPublisher{
 		producer: producer,
 		logger:   logger,
-	}, nil
+		codec:    JSONCodec{},
+		metrics:  NopMetrics{},
+	}
+	for _, opt := range opts {
+		opt(publisher)
+	}
+
+	return publisher, nil
 }
 
-// Publish 이벤트 발행
+// Publish 이벤트 발행 (이벤트 타입이면 메타데이터 헤더 자동 추가)
 func (p *KafkaPublisher) Publish(ctx context.Context, topic string, key string, event interface{}) error {
-	payload, err := json.Marshal(event)
+	return p.PublishWithHeaders(ctx, topic, key, eventHeaders(event), event)
+}
+
+// PublishWithHeaders 헤더와 함께 이벤트 발행
+func (p *KafkaPublisher) PublishWithHeaders(ctx context.Context, topic string, key string, headers Headers, event interface{}) error {
+	payload, contentType, err := encodePayload(p.codec, event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return err
+	}
+
+	headers = headers.clone()
+	headers[HeaderContentType] = contentType
+	if p.serviceName != "" && headers.Get(HeaderProducerService) == "" {
+		headers[HeaderProducerService] = p.serviceName
 	}
 
 	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(payload),
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(payload),
+		Headers: headers.toRecordHeaders(),
 	}
 
 	partition, offset, err := p.producer.SendMessage(msg)
+	p.metrics.MessagePublished(topic, err)
 	if err != nil {
 		p.logger.Error("failed to send message",
 			zap.Error(err),
@@ -100,13 +160,98 @@ type KafkaConsumer struct {
 	consumerGroup sarama.ConsumerGroup
 	handler       MessageHandler
 	logger        *zap.Logger
+
+	retryPolicy   *RetryPolicy
+	retryProducer sarama.SyncProducer
+
+	rebalanceHandler RebalanceHandler
+	drainTimeout     time.Duration
+	workers          int
+	metrics          Metrics
+	limiters         map[string]*rateLimiter
+	pauser           *partitionPauser
+
+	mu      sync.Mutex
+	stop    context.CancelFunc
+	stopped chan struct{}
+}
+
+// ConsumerOption Kafka 구독자 옵션
+type ConsumerOption func(*consumerOptions)
+
+type consumerOptions struct {
+	retryPolicy      *RetryPolicy
+	rebalanceHandler RebalanceHandler
+	drainTimeout     time.Duration
+	workers          int
+	metrics          Metrics
+	rateLimits       map[string]RateLimit
+}
+
+// DefaultDrainTimeout 종료 시 처리 중인 메시지를 기다리는 기본 시간
+const DefaultDrainTimeout = 10 * time.Second
+
+// WithRetryPolicy 핸들러 실패 시 재시도 토픽과 DLQ로 메시지를 라우팅
+func WithRetryPolicy(policy RetryPolicy) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithRebalanceHandler 파티션 할당/해제 콜백 등록
+func WithRebalanceHandler(handler RebalanceHandler) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.rebalanceHandler = handler
+	}
+}
+
+// WithDrainTimeout 종료 시 처리 중인 핸들러를 기다리는 최대 시간
+func WithDrainTimeout(timeout time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.drainTimeout = timeout
+	}
+}
+
+// WithKeyedConcurrency 파티션 내에서 키가 다른 메시지를 workers 개의 고루틴으로 병렬 처리
+//
+// 같은 키(주문 ID)의 메시지는 같은 워커에서 순서대로 처리된다. 1 이하이면 순차 처리.
+func WithKeyedConcurrency(workers int) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.workers = workers
+	}
+}
+
+// WithConsumerMetrics 수신 수, 파티션 lag, 핸들러 실행 시간과 실패 계측
+func WithConsumerMetrics(metrics Metrics) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.metrics = metrics
+	}
+}
+
+// WithRateLimit 토픽의 초당 처리 메시지 수 제한 (재시도 토픽도 원본 토픽의 제한을 공유)
+func WithRateLimit(topic string, perSecond float64, burst int) ConsumerOption {
+	return func(o *consumerOptions) {
+		if perSecond <= 0 {
+			return
+		}
+		if o.rateLimits == nil {
+			o.rateLimits = make(map[string]RateLimit)
+		}
+		o.rateLimits[topic] = RateLimit{PerSecond: perSecond, Burst: burst}
+	}
 }
 
 // NewKafkaConsumer Kafka 구독자 생성
-func NewKafkaConsumer(brokers []string, groupID string, logger *zap.Logger) (*KafkaConsumer, error) {
+func NewKafkaConsumer(brokers []string, groupID string, logger *zap.Logger, opts ...ConsumerOption) (*KafkaConsumer, error) {
+	options := &consumerOptions{drainTimeout: DefaultDrainTimeout, metrics: NopMetrics{}}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	config := sarama.NewConfig()
 	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.IsolationLevel = sarama.ReadCommitted // 트랜잭션 발행자의 중단된 메시지는 읽지 않음
 	config.Consumer.Return.Errors = true
 
 	consumerGroup, err := sarama.NewConsumerGroup(brokers, groupID, config)
@@ -114,24 +259,99 @@ func NewKafkaConsumer(brokers []string, groupID string, logger *zap.Logger) (*Ka
 		return nil, fmt.Errorf("failed to create consumer group: %w", err)
 	}
 
-	return &KafkaConsumer{
-		consumerGroup: consumerGroup,
-		logger:        logger,
-	}, nil
+	consumer := &KafkaConsumer{
+		consumerGroup:    consumerGroup,
+		logger:           logger,
+		retryPolicy:      options.retryPolicy,
+		rebalanceHandler: options.rebalanceHandler,
+		drainTimeout:     options.drainTimeout,
+		workers:          options.workers,
+		metrics:          options.metrics,
+		limiters:         make(map[string]*rateLimiter),
+	}
+	for topic, limit := range options.rateLimits {
+		consumer.limiters[topic] = newRateLimiter(limit)
+	}
+	consumer.pauser = newPartitionPauser(
+		func(topic string, partition int32) {
+			consumerGroup.Pause(map[string][]int32{topic: {partition}})
+		},
+		func(topic string, partition int32) {
+			consumerGroup.Resume(map[string][]int32{topic: {partition}})
+		},
+	)
+
+	if options.retryPolicy != nil {
+		producerConfig := sarama.NewConfig()
+		producerConfig.Producer.Return.Successes = true
+		producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+		producerConfig.Producer.Retry.Max = 5
+
+		producer, err := sarama.NewSyncProducer(brokers, producerConfig)
+		if err != nil {
+			consumerGroup.Close()
+			return nil, fmt.Errorf("failed to create retry producer: %w", err)
+		}
+		consumer.retryProducer = producer
+	}
+
+	return consumer, nil
 }
 
-// Subscribe 토픽 구독
+// Subscribe 토픽 구독 (백그라운드에서 Run 실행, Close 호출 시 종료)
 func (c *KafkaConsumer) Subscribe(topics []string, handler MessageHandler) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	c.mu.Lock()
+	if c.stop != nil {
+		c.mu.Unlock()
+		cancel()
+		return fmt.Errorf("consumer is already subscribed")
+	}
+	c.stop = cancel
+	c.stopped = stopped
+	c.mu.Unlock()
+
+	go func() {
+		defer close(stopped)
+		if err := c.Run(ctx, topics, handler); err != nil {
+			c.logger.Error("consumer stopped with error", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// Run 컨텍스트가 취소될 때까지 토픽을 구독
+//
+// 취소되면 새 메시지 수신을 멈추고, 처리 중인 핸들러가 끝날 때까지 drain 타임아웃 동안
+// 기다린 뒤 마지막으로 처리한 오프셋을 커밋하고 반환한다. 반환 후 다시 호출할 수 있다.
+func (c *KafkaConsumer) Run(ctx context.Context, topics []string, handler MessageHandler) error {
 	c.handler = handler
 
-	ctx := context.Background()
+	// 재시도 정책이 있으면 재시도 티어 토픽도 함께 구독
+	if c.retryPolicy != nil {
+		topics = append(append([]string{}, topics...), c.retryPolicy.retryTopics(topics)...)
+	}
+
+	// 핸들러 컨텍스트는 구독 취소와 분리하고 drain 타임아웃이 지나야 취소
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
 	consumerHandler := &consumerGroupHandler{
-		consumer: c,
+		consumer:   c,
+		handlerCtx: handlerCtx,
 	}
 
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		for {
 			if err := c.consumerGroup.Consume(ctx, topics, consumerHandler); err != nil {
+				if stderrors.Is(err, sarama.ErrClosedConsumerGroup) {
+					return
+				}
 				c.logger.Error("error from consumer", zap.Error(err))
 			}
 
@@ -141,50 +361,305 @@ func (c *KafkaConsumer) Subscribe(topics []string, handler MessageHandler) error
 		}
 	}()
 
-	return nil
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.logger.Info("draining consumer", zap.Duration("timeout", c.drainTimeout))
+
+	timer := time.NewTimer(c.drainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		c.logger.Info("consumer drained")
+		return nil
+	case <-timer.C:
+		cancelHandlers()
+		return fmt.Errorf("consumer drain timed out after %s", c.drainTimeout)
+	}
 }
 
-// Close 구독자 종료
+// Close 구독자 종료 (Subscribe로 시작한 구독은 drain 후 종료)
 func (c *KafkaConsumer) Close() error {
-	return c.consumerGroup.Close()
+	c.mu.Lock()
+	stop, stopped := c.stop, c.stopped
+	c.stop, c.stopped = nil, nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-stopped
+	}
+
+	err := c.consumerGroup.Close()
+	if c.retryProducer != nil {
+		if perr := c.retryProducer.Close(); perr != nil && err == nil {
+			err = perr
+		}
+	}
+	return err
+}
+
+// routeFailure 처리에 실패한 메시지를 다음 재시도 티어 또는 DLQ로 발행
+func (c *KafkaConsumer) routeFailure(message *sarama.ConsumerMessage, state retryState, handlerErr error) error {
+	attempt := state.attempt + 1
+	topic, delay, dlq := c.retryPolicy.retryRoute(state.originalTopic, attempt, handlerErr)
+
+	overrides := map[string]string{
+		HeaderOriginalTopic:  state.originalTopic,
+		HeaderRetryAttempt:   strconv.Itoa(attempt),
+		HeaderError:          handlerErr.Error(),
+		HeaderRetryNotBefore: strconv.FormatInt(time.Now().Add(delay).UnixMilli(), 10),
+	}
+	// 최초 실패 위치만 기록 (재시도 토픽의 위치로 덮어쓰지 않음)
+	if state.attempt == 0 {
+		overrides[HeaderOriginalPartition] = strconv.FormatInt(int64(message.Partition), 10)
+		overrides[HeaderOriginalOffset] = strconv.FormatInt(message.Offset, 10)
+	}
+
+	_, _, err := c.retryProducer.SendMessage(&sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: withHeaders(message.Headers, overrides),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to route message to %s: %w", topic, err)
+	}
+
+	if dlq {
+		c.logger.Error("message sent to dead letter queue",
+			zap.String("topic", topic),
+			zap.String("originalTopic", state.originalTopic),
+			zap.Int("attempt", attempt),
+			zap.Error(handlerErr))
+	} else {
+		c.logger.Warn("message scheduled for retry",
+			zap.String("topic", topic),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(handlerErr))
+	}
+
+	return nil
 }
 
 // consumerGroupHandler Kafka 컨슈머 그룹 핸들러
 type consumerGroupHandler struct {
-	consumer *KafkaConsumer
+	consumer   *KafkaConsumer
+	handlerCtx context.Context
+}
+
+func (h *consumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	h.consumer.logger.Info("partitions assigned", zap.Any("claims", session.Claims()))
+
+	if h.consumer.rebalanceHandler != nil {
+		return h.consumer.rebalanceHandler.Setup(h.handlerCtx, session.Claims())
+	}
+	return nil
 }
 
-func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
-func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+func (h *consumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	var err error
+	if h.consumer.rebalanceHandler != nil {
+		err = h.consumer.rebalanceHandler.Cleanup(h.handlerCtx, session.Claims())
+	}
+
+	// 세션 종료 전 처리 완료된 오프셋을 동기 커밋
+	session.Commit()
+	h.consumer.logger.Info("partitions released", zap.Any("claims", session.Claims()))
+
+	return err
+}
 
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for message := range claim.Messages() {
-		msg := &Message{
-			Topic:     message.Topic,
-			Partition: message.Partition,
-			Offset:    message.Offset,
-			Key:       message.Key,
-			Value:     message.Value,
+	if h.consumer.workers > 1 {
+		return h.consumeKeyed(session, claim)
+	}
+
+	for {
+		var message *sarama.ConsumerMessage
+		select {
+		case <-session.Context().Done():
+			return nil
+		case m, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			message = m
 		}
+		h.recordReceived(claim, message)
 
-		h.consumer.logger.Info("message received",
-			zap.String("topic", message.Topic),
-			zap.Int32("partition", message.Partition),
-			zap.Int64("offset", message.Offset),
-			zap.String("key", string(message.Key)))
+		// 종료 중이면 버퍼에 남은 메시지는 처리하지 않음
+		if session.Context().Err() != nil {
+			return nil
+		}
 
-		if err := h.consumer.handler(session.Context(), msg); err != nil {
-			h.consumer.logger.Error("failed to handle message",
-				zap.Error(err),
-				zap.String("topic", message.Topic),
-				zap.Int64("offset", message.Offset))
-			// 에러가 발생해도 메시지를 마킹하여 재처리 방지 (멱등성 보장 필요)
+		handled, err := h.process(session.Context(), message)
+		if err != nil {
+			return err
+		}
+		if !handled {
+			return nil
 		}
 
 		session.MarkMessage(message, "")
 	}
+}
 
-	return nil
+// consumeKeyed 키가 다른 메시지는 병렬로, 같은 키는 순서대로 처리
+//
+// 오프셋은 앞선 메시지가 모두 완료된 연속 구간까지만 마킹한다.
+func (h *consumerGroupHandler) consumeKeyed(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx, cancel := context.WithCancel(session.Context())
+	defer cancel()
+
+	tracker := newOffsetTracker()
+
+	var (
+		failOnce sync.Once
+		fatalErr error
+	)
+
+	dispatcher := newKeyedDispatcher(h.consumer.workers, func(message *sarama.ConsumerMessage) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		handled, err := h.process(ctx, message)
+		if err != nil {
+			failOnce.Do(func() {
+				fatalErr = err
+				cancel()
+			})
+			return
+		}
+		if !handled {
+			return
+		}
+
+		if last, ok := tracker.complete(message.Offset); ok {
+			session.MarkOffset(message.Topic, message.Partition, last+1, "")
+		}
+	})
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case message, ok := <-claim.Messages():
+			if !ok {
+				break loop
+			}
+			h.recordReceived(claim, message)
+			tracker.add(message.Offset)
+			dispatcher.dispatch(message)
+		}
+	}
+
+	dispatcher.close()
+	return fatalErr
+}
+
+// recordReceived 수신 메시지 수와 파티션 lag 기록
+func (h *consumerGroupHandler) recordReceived(claim sarama.ConsumerGroupClaim, message *sarama.ConsumerMessage) {
+	h.consumer.metrics.MessageConsumed(message.Topic, message.Partition)
+	h.consumer.metrics.ConsumerLag(message.Topic, message.Partition, claim.HighWaterMarkOffset()-message.Offset-1)
+}
+
+// process 메시지 하나를 처리
+//
+// 재시도 지연 대기 중 컨텍스트가 취소되면 handled=false 를 반환하며, 이 경우 오프셋을 마킹하지 않는다.
+// 재시도 토픽/DLQ 발행에 실패하면 에러를 반환한다.
+func (h *consumerGroupHandler) process(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+	state := parseRetryState(message)
+
+	// 재시도 토픽의 메시지는 지연 시간이 지날 때까지 대기
+	if err := waitUntil(ctx, state.notBefore); err != nil {
+		return false, nil
+	}
+
+	msg := &Message{
+		Topic:     state.originalTopic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       message.Key,
+		Value:     message.Value,
+		Headers:   fromRecordHeaders(message.Headers),
+	}
+
+	h.consumer.logger.Info("message received",
+		zap.String("topic", message.Topic),
+		zap.Int32("partition", message.Partition),
+		zap.Int64("offset", message.Offset),
+		zap.String("key", string(message.Key)),
+		zap.String("eventType", string(msg.EventType())),
+		zap.String("eventId", msg.EventID()),
+		zap.String("correlationId", msg.CorrelationID()))
+
+	// 토픽별 처리량 제한
+	if limiter, ok := h.consumer.limiters[state.originalTopic]; ok {
+		if err := limiter.wait(ctx); err != nil {
+			return false, nil
+		}
+	}
+
+	handled, err := h.handle(ctx, message, msg)
+	if !handled {
+		return false, nil
+	}
+
+	if err != nil {
+		h.consumer.logger.Error("failed to handle message",
+			zap.Error(err),
+			zap.String("topic", message.Topic),
+			zap.Int64("offset", message.Offset))
+
+		// 재시도 정책이 있으면 재시도 토픽/DLQ로 넘긴 뒤에만 커밋
+		if h.consumer.retryPolicy != nil {
+			if err := h.consumer.routeFailure(message, state, err); err != nil {
+				h.consumer.logger.Error("failed to route failed message", zap.Error(err))
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// handle 핸들러 호출 (back-pressure 요청 시 파티션을 멈추고 대기 후 같은 메시지를 다시 처리)
+//
+// 대기 중 컨텍스트가 취소되면 handled=false 를 반환하며, err 는 핸들러가 반환한 에러다.
+func (h *consumerGroupHandler) handle(ctx context.Context, message *sarama.ConsumerMessage, msg *Message) (bool, error) {
+	for {
+		start := time.Now()
+		err := h.consumer.handler(h.handlerCtx, msg)
+		h.consumer.metrics.HandlerObserved(msg.Topic, time.Since(start), err)
+
+		delay, ok := backpressureDelay(err)
+		if !ok {
+			return true, err
+		}
+
+		h.consumer.logger.Warn("handler requested backpressure, pausing partition",
+			zap.String("topic", message.Topic),
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		h.consumer.pauser.acquire(message.Topic, message.Partition)
+		waitErr := waitUntil(ctx, time.Now().Add(delay))
+		h.consumer.pauser.release(message.Topic, message.Partition)
+
+		if waitErr != nil {
+			return false, nil
+		}
+	}
 }
 
 // PublishOrderID Order ID를 키로 사용하여 발행하는 헬퍼 함수
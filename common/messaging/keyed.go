@@ -0,0 +1,89 @@
+package messaging
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/IBM/sarama"
+)
+
+// offsetTracker 파티션 내에서 연속으로 처리 완료된 오프셋을 추적
+//
+// 메시지는 수신 순서대로 등록되고, 앞선 메시지가 모두 완료된 경우에만 커밋 위치가 전진한다.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending []int64
+	done    map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{done: make(map[int64]bool)}
+}
+
+// add 수신한 오프셋 등록
+func (t *offsetTracker) add(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, offset)
+}
+
+// complete 처리 완료 표시 후 새로 커밋 가능한 마지막 오프셋 반환 (없으면 false)
+func (t *offsetTracker) complete(offset int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[offset] = true
+
+	last, advanced := int64(0), false
+	for len(t.pending) > 0 && t.done[t.pending[0]] {
+		last = t.pending[0]
+		delete(t.done, last)
+		t.pending = t.pending[1:]
+		advanced = true
+	}
+	return last, advanced
+}
+
+// keyedDispatcher 같은 키는 같은 워커로 보내 키 단위 순서를 보장하는 디스패처
+type keyedDispatcher struct {
+	queues []chan *sarama.ConsumerMessage
+	wg     sync.WaitGroup
+}
+
+func newKeyedDispatcher(workers int, process func(*sarama.ConsumerMessage)) *keyedDispatcher {
+	d := &keyedDispatcher{queues: make([]chan *sarama.ConsumerMessage, workers)}
+	for i := range d.queues {
+		queue := make(chan *sarama.ConsumerMessage, 1)
+		d.queues[i] = queue
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for message := range queue {
+				process(message)
+			}
+		}()
+	}
+	return d
+}
+
+// dispatch 메시지 키에 해당하는 워커 큐에 전달 (키가 없으면 순서 보장 불필요)
+func (d *keyedDispatcher) dispatch(message *sarama.ConsumerMessage) {
+	var slot uint32
+	if len(message.Key) == 0 {
+		slot = uint32(message.Offset % int64(len(d.queues)))
+	} else {
+		h := fnv.New32a()
+		h.Write(message.Key)
+		slot = h.Sum32() % uint32(len(d.queues))
+	}
+	d.queues[slot] <- message
+}
+
+// close 큐를 닫고 모든 워커 종료 대기
+func (d *keyedDispatcher) close() {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
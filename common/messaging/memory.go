@@ -0,0 +1,419 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	_ Publisher = (*MemoryPublisher)(nil)
+	_ Consumer  = (*MemoryConsumer)(nil)
+)
+
+// MemoryBroker 프로세스 내 메모리 기반 메시지 브로커 (테스트, 단일 프로세스 실행용)
+//
+// 토픽은 키 해시로 파티셔닝되며, 컨슈머 그룹별로 파티션 오프셋을 추적한다.
+// 같은 그룹의 컨슈머들은 파티션을 나누어 처리하고, 다른 그룹은 모든 메시지를 각각 수신한다.
+type MemoryBroker struct {
+	mu         sync.Mutex
+	partitions int
+	topics     map[string][][]*Message
+	groups     map[string]*memoryGroup
+	roundRobin int
+	notify     chan struct{}
+}
+
+// memoryGroup 컨슈머 그룹 상태
+type memoryGroup struct {
+	offsets map[string][]int64
+	busy    map[string][]bool
+	members []*MemoryConsumer
+}
+
+// NewMemoryBroker 메모리 브로커 생성 (partitions 는 토픽당 파티션 수)
+func NewMemoryBroker(partitions int) *MemoryBroker {
+	if partitions < 1 {
+		partitions = 1
+	}
+	return &MemoryBroker{
+		partitions: partitions,
+		topics:     make(map[string][][]*Message),
+		groups:     make(map[string]*memoryGroup),
+		notify:     make(chan struct{}),
+	}
+}
+
+// NewPublisher 브로커에 발행하는 Publisher 생성 (JSON 직렬화)
+func (b *MemoryBroker) NewPublisher(serviceName string) *MemoryPublisher {
+	return b.NewPublisherWithCodec(serviceName, JSONCodec{})
+}
+
+// NewPublisherWithCodec 지정한 코덱으로 직렬화하는 Publisher 생성
+func (b *MemoryBroker) NewPublisherWithCodec(serviceName string, codec Codec) *MemoryPublisher {
+	return &MemoryPublisher{broker: b, serviceName: serviceName, codec: codec}
+}
+
+// NewConsumer 브로커에서 구독하는 Consumer 생성 (핸들러가 실패하면 재시도 없이 DLQ 에 보관)
+func (b *MemoryBroker) NewConsumer(groupID string, logger *zap.Logger) *MemoryConsumer {
+	return b.NewConsumerWithRetry(groupID, logger, RetryPolicy{})
+}
+
+// NewConsumerWithRetry 실패한 메시지를 정책의 지연만큼 기다렸다가 같은 자리에서 다시 처리하는 Consumer 생성
+//
+// Kafka 의 재시도 토픽과 달리 파티션을 막고 기다리며, 비즈니스 에러와 역직렬화 에러는 바로 DLQ 에 보관한다.
+func (b *MemoryBroker) NewConsumerWithRetry(groupID string, logger *zap.Logger, policy RetryPolicy) *MemoryConsumer {
+	return &MemoryConsumer{broker: b, groupID: groupID, logger: logger, retry: policy}
+}
+
+// Messages 토픽에 저장된 모든 메시지 조회 (파티션 순서대로)
+func (b *MemoryBroker) Messages(topic string) []*Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var result []*Message
+	for _, partition := range b.topics[topic] {
+		result = append(result, partition...)
+	}
+	return result
+}
+
+// Lag 컨슈머 그룹이 아직 처리하지 않은 메시지 수
+func (b *MemoryBroker) Lag(groupID string, topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lag int64
+	group := b.groups[groupID]
+	for partition, messages := range b.topics[topic] {
+		committed := int64(0)
+		if group != nil && group.offsets[topic] != nil {
+			committed = group.offsets[topic][partition]
+		}
+		lag += int64(len(messages)) - committed
+	}
+	return lag
+}
+
+// append 메시지를 파티션에 추가하고 대기 중인 컨슈머를 깨움
+func (b *MemoryBroker) append(topic string, key string, headers Headers, value []byte) *Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	partitions := b.topic(topic)
+
+	var partition int
+	if key == "" {
+		partition = b.roundRobin % b.partitions
+		b.roundRobin++
+	} else {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		partition = int(h.Sum32() % uint32(b.partitions))
+	}
+
+	msg := &Message{
+		Topic:     topic,
+		Partition: int32(partition),
+		Offset:    int64(len(partitions[partition])),
+		Key:       []byte(key),
+		Value:     value,
+		Headers:   headers,
+	}
+	partitions[partition] = append(partitions[partition], msg)
+
+	close(b.notify)
+	b.notify = make(chan struct{})
+
+	return msg
+}
+
+// topic 토픽 조회 (없으면 생성, 호출자가 잠금 보유)
+func (b *MemoryBroker) topic(name string) [][]*Message {
+	partitions, ok := b.topics[name]
+	if !ok {
+		partitions = make([][]*Message, b.partitions)
+		b.topics[name] = partitions
+	}
+	return partitions
+}
+
+// group 컨슈머 그룹 조회 (없으면 생성, 호출자가 잠금 보유)
+func (b *MemoryBroker) group(groupID string) *memoryGroup {
+	group, ok := b.groups[groupID]
+	if !ok {
+		group = &memoryGroup{
+			offsets: make(map[string][]int64),
+			busy:    make(map[string][]bool),
+		}
+		b.groups[groupID] = group
+	}
+	return group
+}
+
+// join 그룹에 컨슈머 추가 (파티션은 멤버 수 기준으로 재분배됨)
+func (b *MemoryBroker) join(consumer *MemoryConsumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.group(consumer.groupID)
+	group.members = append(group.members, consumer)
+}
+
+// leave 그룹에서 컨슈머 제거
+func (b *MemoryBroker) leave(consumer *MemoryConsumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.group(consumer.groupID)
+	for i, member := range group.members {
+		if member == consumer {
+			group.members = append(group.members[:i], group.members[i+1:]...)
+			break
+		}
+	}
+
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// claimNext 컨슈머에 할당된 파티션에서 처리할 다음 메시지를 점유
+//
+// 메시지가 없으면 새 메시지 발행 시 닫히는 채널을 반환한다.
+func (b *MemoryBroker) claimNext(consumer *MemoryConsumer, topics []string) (*Message, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.group(consumer.groupID)
+
+	index := -1
+	for i, member := range group.members {
+		if member == consumer {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, b.notify
+	}
+
+	for _, topic := range topics {
+		partitions := b.topic(topic)
+		if group.offsets[topic] == nil {
+			group.offsets[topic] = make([]int64, b.partitions)
+			group.busy[topic] = make([]bool, b.partitions)
+		}
+
+		for partition := range partitions {
+			if partition%len(group.members) != index || group.busy[topic][partition] {
+				continue
+			}
+
+			offset := group.offsets[topic][partition]
+			if offset >= int64(len(partitions[partition])) {
+				continue
+			}
+
+			group.busy[topic][partition] = true
+			return partitions[partition][offset], nil
+		}
+	}
+
+	return nil, b.notify
+}
+
+// commit 메시지 처리 완료 후 그룹 오프셋 전진
+func (b *MemoryBroker) commit(groupID string, msg *Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.group(groupID)
+	group.offsets[msg.Topic][msg.Partition] = msg.Offset + 1
+	group.busy[msg.Topic][msg.Partition] = false
+
+	// 재분배로 파티션을 넘겨받은 컨슈머가 기다리고 있을 수 있음
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// release 오프셋을 전진시키지 않고 파티션 점유 해제 (다음에 같은 메시지를 다시 전달)
+func (b *MemoryBroker) release(groupID string, msg *Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.group(groupID).busy[msg.Topic][msg.Partition] = false
+
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// MemoryPublisher 메모리 브로커 기반 이벤트 발행자
+type MemoryPublisher struct {
+	broker      *MemoryBroker
+	serviceName string
+	codec       Codec
+}
+
+// Publish 이벤트 발행 (이벤트 타입이면 메타데이터 헤더 자동 추가)
+func (p *MemoryPublisher) Publish(ctx context.Context, topic string, key string, event interface{}) error {
+	return p.PublishWithHeaders(ctx, topic, key, eventHeaders(event), event)
+}
+
+// PublishWithHeaders 헤더와 함께 이벤트 발행
+func (p *MemoryPublisher) PublishWithHeaders(ctx context.Context, topic string, key string, headers Headers, event interface{}) error {
+	payload, contentType, err := encodePayload(p.codec, event)
+	if err != nil {
+		return err
+	}
+
+	headers = headers.clone()
+	headers[HeaderContentType] = contentType
+	if p.serviceName != "" && headers.Get(HeaderProducerService) == "" {
+		headers[HeaderProducerService] = p.serviceName
+	}
+
+	p.broker.append(topic, key, headers, payload)
+	return nil
+}
+
+// Close 발행자 종료
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
+
+// MemoryConsumer 메모리 브로커 기반 이벤트 구독자
+//
+// 핸들러가 실패한 메시지는 에러 헤더와 함께 <topic>.dlq 토픽에 보관된다.
+type MemoryConsumer struct {
+	broker  *MemoryBroker
+	groupID string
+	logger  *zap.Logger
+	retry   RetryPolicy
+
+	mu      sync.Mutex
+	stop    context.CancelFunc
+	stopped chan struct{}
+}
+
+// Subscribe 토픽 구독 (백그라운드에서 Run 실행, Close 호출 시 종료)
+func (c *MemoryConsumer) Subscribe(topics []string, handler MessageHandler) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	c.mu.Lock()
+	if c.stop != nil {
+		c.mu.Unlock()
+		cancel()
+		return fmt.Errorf("consumer is already subscribed")
+	}
+	c.stop = cancel
+	c.stopped = stopped
+	c.mu.Unlock()
+
+	go func() {
+		defer close(stopped)
+		_ = c.Run(ctx, topics, handler)
+	}()
+
+	return nil
+}
+
+// Run 컨텍스트가 취소될 때까지 토픽을 구독 (처리 중인 메시지는 완료 후 반환)
+func (c *MemoryConsumer) Run(ctx context.Context, topics []string, handler MessageHandler) error {
+	c.broker.join(c)
+	defer c.broker.leave(c)
+
+	topics = append([]string{}, topics...)
+	sort.Strings(topics)
+
+	handlerCtx := context.WithoutCancel(ctx)
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		msg, wait := c.broker.claimNext(c, topics)
+		if msg == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-wait:
+			}
+			continue
+		}
+
+		if !c.handle(ctx, handlerCtx, handler, msg) {
+			c.broker.release(c.groupID, msg)
+			continue
+		}
+		c.broker.commit(c.groupID, msg)
+	}
+}
+
+// handle 핸들러 호출 (재시도 정책을 다 쓰거나 재시도할 수 없는 에러면 DLQ 토픽에 보관)
+//
+// 핸들러가 back-pressure 를 요청하면 대기 후 다시 호출하며, 대기 중 구독이 취소되면 false 를 반환한다.
+func (c *MemoryConsumer) handle(ctx, handlerCtx context.Context, handler MessageHandler, msg *Message) bool {
+	attempt := 0
+	for {
+		delivered := *msg
+		err := handler(handlerCtx, &delivered)
+		if err == nil {
+			return true
+		}
+
+		if delay, ok := backpressureDelay(err); ok {
+			if waitUntil(ctx, time.Now().Add(delay)) != nil {
+				return false
+			}
+			continue
+		}
+
+		if attempt < len(c.retry.Delays) && !isPermanentFailure(err) {
+			attempt++
+			c.logger.Warn("failed to handle message, retrying",
+				zap.Error(err),
+				zap.String("topic", msg.Topic),
+				zap.Int64("offset", msg.Offset),
+				zap.Int("attempt", attempt))
+			if waitUntil(ctx, time.Now().Add(c.retry.Delays[attempt-1])) != nil {
+				return false
+			}
+			continue
+		}
+
+		c.logger.Error("failed to handle message",
+			zap.Error(err),
+			zap.String("topic", msg.Topic),
+			zap.Int64("offset", msg.Offset))
+
+		headers := msg.Headers.clone()
+		headers[HeaderOriginalTopic] = msg.Topic
+		headers[HeaderRetryAttempt] = strconv.Itoa(attempt)
+		headers[HeaderError] = err.Error()
+
+		c.broker.append(DLQTopic(msg.Topic), string(msg.Key), headers, msg.Value)
+		return true
+	}
+}
+
+// Close 구독자 종료 (Subscribe로 시작한 구독은 처리 중인 메시지 완료 후 종료)
+func (c *MemoryConsumer) Close() error {
+	c.mu.Lock()
+	stop, stopped := c.stop, c.stopped
+	c.stop, c.stopped = nil, nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-stopped
+	}
+	return nil
+}
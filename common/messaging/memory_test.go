@@ -0,0 +1,491 @@
+package messaging_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"go.uber.org/zap"
+)
+
+func TestMemoryBrokerKeyedOrdering(t *testing.T) {
+	const (
+		topic    = "ordering.test"
+		keys     = 8
+		perKey   = 25
+		members  = 3
+		messages = keys * perKey
+	)
+
+	broker := messaging.NewMemoryBroker(4)
+	publisher := broker.NewPublisher("test-service")
+	for seq := 0; seq < perKey; seq++ {
+		for k := 0; k < keys; k++ {
+			payload := map[string]int{"key": k, "seq": seq}
+			if err := publisher.Publish(context.Background(), topic, strconv.Itoa(k), payload); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+		}
+	}
+
+	// 같은 키는 항상 같은 파티션에 기록
+	partitionOf := make(map[string]int32)
+	for _, msg := range broker.Messages(topic) {
+		key := string(msg.Key)
+		if partition, ok := partitionOf[key]; ok && partition != msg.Partition {
+			t.Fatalf("key %s written to partitions %d and %d", key, partition, msg.Partition)
+		}
+		partitionOf[key] = msg.Partition
+	}
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[string][]int)
+	)
+	handler := messaging.Typed(func(ctx context.Context, payload map[string]int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strconv.Itoa(payload["key"])
+		seen[key] = append(seen[key], payload["seq"])
+		return nil
+	})
+
+	// 같은 그룹의 여러 컨슈머가 파티션을 나누어 동시에 처리해도 키 단위 순서는 유지
+	for i := 0; i < members; i++ {
+		runConsumer(t, broker.NewConsumer("ordering-group", zap.NewNop()), []string{topic}, handler)
+	}
+
+	eventually(t, func() bool { return broker.Lag("ordering-group", topic) == 0 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	total := 0
+	for key, seqs := range seen {
+		total += len(seqs)
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("key %s processed out of order: %v", key, seqs)
+			}
+		}
+	}
+	if total != messages {
+		t.Fatalf("expected %d messages, got %d", messages, total)
+	}
+}
+
+func TestMemoryBrokerConsumerGroupOffsets(t *testing.T) {
+	const topic = "offsets.test"
+
+	broker := messaging.NewMemoryBroker(2)
+	publisher := broker.NewPublisher("test-service")
+	publish := func(from, to int) {
+		t.Helper()
+		for i := from; i < to; i++ {
+			if err := publisher.Publish(context.Background(), topic, strconv.Itoa(i), map[string]int{"n": i}); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+		}
+	}
+	publish(0, 10)
+
+	counter := func() (messaging.MessageHandler, func() []int) {
+		var (
+			mu   sync.Mutex
+			seen []int
+		)
+		handler := messaging.Typed(func(ctx context.Context, payload map[string]int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, payload["n"])
+			if payload["n"] == 3 {
+				return stderrors.New("boom")
+			}
+			return nil
+		})
+		return handler, func() []int {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]int(nil), seen...)
+		}
+	}
+
+	// 그룹 A 가 처리하고 멈추면 오프셋이 남아, 다시 시작했을 때 새 메시지만 받는다
+	handlerA, seenA := counter()
+	stopA := runConsumer(t, broker.NewConsumer("group-a", zap.NewNop()), []string{topic}, handlerA)
+	eventually(t, func() bool { return broker.Lag("group-a", topic) == 0 })
+	stopA()
+
+	if got := len(seenA()); got != 10 {
+		t.Fatalf("expected group-a to process 10 messages, got %d", got)
+	}
+	// 실패한 메시지는 DLQ 에 보관되고 오프셋은 전진
+	dlq := broker.Messages(messaging.DLQTopic(topic))
+	if len(dlq) != 1 || dlq[0].Headers.Get(messaging.HeaderError) == "" {
+		t.Fatalf("expected 1 dead-lettered message with error header, got %d", len(dlq))
+	}
+
+	// 다른 그룹은 아직 아무것도 처리하지 않음
+	if lag := broker.Lag("group-b", topic); lag != 10 {
+		t.Fatalf("expected group-b lag 10, got %d", lag)
+	}
+
+	publish(10, 15)
+	if lag := broker.Lag("group-a", topic); lag != 5 {
+		t.Fatalf("expected group-a lag 5, got %d", lag)
+	}
+
+	handlerA2, seenA2 := counter()
+	runConsumer(t, broker.NewConsumer("group-a", zap.NewNop()), []string{topic}, handlerA2)
+	eventually(t, func() bool { return broker.Lag("group-a", topic) == 0 })
+	for _, n := range seenA2() {
+		if n < 10 {
+			t.Fatalf("restarted group-a reprocessed committed message %d", n)
+		}
+	}
+
+	handlerB, seenB := counter()
+	runConsumer(t, broker.NewConsumer("group-b", zap.NewNop()), []string{topic}, handlerB)
+	eventually(t, func() bool { return broker.Lag("group-b", topic) == 0 })
+	if got := len(seenB()); got != 15 {
+		t.Fatalf("expected group-b to process all 15 messages, got %d", got)
+	}
+}
+
+func TestMemoryBrokerConsumerRetry(t *testing.T) {
+	const topic = "retry.test"
+
+	broker := messaging.NewMemoryBroker(1)
+	publisher := broker.NewPublisher("test-service")
+	for _, kind := range []string{"transient", "business"} {
+		if err := publisher.Publish(context.Background(), topic, kind, map[string]string{"kind": kind}); err != nil {
+			t.Fatalf("failed to publish: %v", err)
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		calls = make(map[string]int)
+	)
+	handler := messaging.Typed(func(ctx context.Context, payload map[string]string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		kind := payload["kind"]
+		calls[kind]++
+		switch {
+		case kind == "business":
+			return errors.New(errors.ErrCodeOutOfStock, "out of stock")
+		case calls[kind] < 3:
+			return stderrors.New("database unavailable")
+		}
+		return nil
+	})
+
+	policy := messaging.RetryPolicy{Delays: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}}
+	runConsumer(t, broker.NewConsumerWithRetry("retry-group", zap.NewNop(), policy), []string{topic}, handler)
+	eventually(t, func() bool { return broker.Lag("retry-group", topic) == 0 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	// 일시적인 실패는 같은 자리에서 다시 처리되어 성공하고, 비즈니스 에러는 재시도 없이 DLQ 로 이동
+	if calls["transient"] != 3 || calls["business"] != 1 {
+		t.Fatalf("expected 3 transient and 1 business call, got %v", calls)
+	}
+	dlq := broker.Messages(messaging.DLQTopic(topic))
+	if len(dlq) != 1 || string(dlq[0].Key) != "business" {
+		t.Fatalf("expected only the business failure in the DLQ, got %d messages", len(dlq))
+	}
+}
+
+// TestMemoryBrokerChoreographySaga 네 서비스의 이벤트 흐름을 하나의 메모리 브로커로 연결해 SAGA 를 끝까지 실행
+//
+// 서비스마다 컨슈머 그룹, Router, 멱등성 저장소를 따로 두고, 핸들러는 각 서비스의 상태 전이 규칙을
+// 메모리 상태로 흉내 낸다 (결제 → 재고 예약 → 배송, 재고 부족 시 주문 실패와 결제 환불).
+// 브로커의 파티셔닝, 컨슈머 그룹, 멱등성 처리를 검증하는 테스트이며, 실제 서비스 구현을 연결한 실행은
+// services/local 테스트가 담당한다 (서비스별 PostgreSQL 필요).
+func TestMemoryBrokerChoreographySaga(t *testing.T) {
+	broker := messaging.NewMemoryBroker(4)
+	saga := newSagaFixture(t, broker, 10)
+
+	// 성공: 재고 10 중 2 예약 → 배송 시작 → 주문 완료
+	completed := saga.createOrder(1, 2)
+	// 보상: 재고 부족 → 주문 실패, 결제 환불
+	failed := saga.createOrder(2, 20)
+
+	// 같은 이벤트가 다시 전달되어도 결제는 한 번만 처리
+	if err := saga.publish(completed); err != nil {
+		t.Fatalf("failed to publish duplicate event: %v", err)
+	}
+
+	eventually(t, func() bool {
+		return saga.orderStatus(1) == "COMPLETED" && saga.orderStatus(2) == "FAILED" && saga.paymentStatus(2) == "REFUNDED"
+	})
+
+	saga.mu.Lock()
+	defer saga.mu.Unlock()
+	if saga.payments[1] != "COMPLETED" {
+		t.Fatalf("expected payment for order 1 to be COMPLETED, got %s", saga.payments[1])
+	}
+	if saga.charges != 2 {
+		t.Fatalf("expected 2 charges (one per order), got %d", saga.charges)
+	}
+	if saga.stock != 8 {
+		t.Fatalf("expected stock 8, got %d", saga.stock)
+	}
+	if !saga.deliveries[1] || saga.deliveries[2] {
+		t.Fatalf("expected delivery only for order 1, got %v", saga.deliveries)
+	}
+	if _, ok := saga.reservations[failed.OrderID]; ok {
+		t.Fatalf("expected no reservation for failed order")
+	}
+
+	for _, eventType := range events.AllEventTypes() {
+		if dlq := broker.Messages(messaging.DLQTopic(string(eventType))); len(dlq) != 0 {
+			t.Fatalf("expected no dead-lettered %s messages, got %d", eventType, len(dlq))
+		}
+	}
+}
+
+// sagaFixture 메모리 브로커 위의 주문/결제/재고/배송 서비스
+type sagaFixture struct {
+	t         *testing.T
+	publisher *messaging.MemoryPublisher
+
+	mu           sync.Mutex
+	orders       map[int64]string
+	quantities   map[int64]int
+	payments     map[int64]string
+	charges      int
+	stock        int
+	reservations map[int64]int
+	deliveries   map[int64]bool
+}
+
+func newSagaFixture(t *testing.T, broker *messaging.MemoryBroker, stock int) *sagaFixture {
+	s := &sagaFixture{
+		t:            t,
+		publisher:    broker.NewPublisher("saga-test"),
+		orders:       make(map[int64]string),
+		quantities:   make(map[int64]int),
+		payments:     make(map[int64]string),
+		stock:        stock,
+		reservations: make(map[int64]int),
+		deliveries:   make(map[int64]bool),
+	}
+
+	s.startService(broker, "order-service-group", func(r *messaging.Router) {
+		messaging.On[events.PaymentFailedEvent](r, func(ctx context.Context, evt events.PaymentFailedEvent) error {
+			s.setOrder(evt.OrderID, "CANCELED")
+			return nil
+		})
+		messaging.On[events.StockReservationFailedEvent](r, func(ctx context.Context, evt events.StockReservationFailedEvent) error {
+			s.setOrder(evt.OrderID, "FAILED")
+			return nil
+		})
+		messaging.On[events.DeliveryStartedEvent](r, func(ctx context.Context, evt events.DeliveryStartedEvent) error {
+			s.setOrder(evt.OrderID, "COMPLETED")
+			return nil
+		})
+	})
+
+	s.startService(broker, "payment-service-group", func(r *messaging.Router) {
+		messaging.On[events.OrderCreatedEvent](r, func(ctx context.Context, evt events.OrderCreatedEvent) error {
+			s.mu.Lock()
+			s.charges++
+			s.payments[evt.OrderID] = "COMPLETED"
+			s.mu.Unlock()
+
+			return s.publish(events.PaymentCompletedEvent{
+				BaseEvent: nextEvent(evt.BaseEvent, events.EventPaymentCompleted),
+				OrderID:   evt.OrderID,
+				PaymentID: evt.OrderID,
+				Amount:    evt.Amount,
+			})
+		})
+		messaging.On[events.StockReservationFailedEvent](r, func(ctx context.Context, evt events.StockReservationFailedEvent) error {
+			s.mu.Lock()
+			s.payments[evt.OrderID] = "REFUNDED"
+			s.mu.Unlock()
+
+			return s.publish(events.PaymentRefundedEvent{
+				BaseEvent: nextEvent(evt.BaseEvent, events.EventPaymentRefunded),
+				OrderID:   evt.OrderID,
+				PaymentID: evt.OrderID,
+			})
+		})
+	})
+
+	s.startService(broker, "inventory-service-group", func(r *messaging.Router) {
+		messaging.On[events.PaymentCompletedEvent](r, func(ctx context.Context, evt events.PaymentCompletedEvent) error {
+			quantity := s.quantityOf(evt.OrderID)
+
+			s.mu.Lock()
+			if s.stock < quantity {
+				s.mu.Unlock()
+				return s.publish(events.StockReservationFailedEvent{
+					BaseEvent: nextEvent(evt.BaseEvent, events.EventStockReservationFailed),
+					OrderID:   evt.OrderID,
+					Quantity:  quantity,
+					Reason:    "insufficient stock",
+				})
+			}
+			s.stock -= quantity
+			s.reservations[evt.OrderID] = quantity
+			s.mu.Unlock()
+
+			return s.publish(events.StockReservedEvent{
+				BaseEvent:     nextEvent(evt.BaseEvent, events.EventStockReserved),
+				OrderID:       evt.OrderID,
+				ReservationID: evt.OrderID,
+				Quantity:      quantity,
+			})
+		})
+		messaging.On[events.PaymentRefundedEvent](r, func(ctx context.Context, evt events.PaymentRefundedEvent) error {
+			// 예약이 없으면(재고 부족으로 실패) 복구할 재고도 없음
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if quantity, ok := s.reservations[evt.OrderID]; ok {
+				s.stock += quantity
+				delete(s.reservations, evt.OrderID)
+			}
+			return nil
+		})
+	})
+
+	s.startService(broker, "delivery-service-group", func(r *messaging.Router) {
+		messaging.On[events.StockReservedEvent](r, func(ctx context.Context, evt events.StockReservedEvent) error {
+			s.mu.Lock()
+			s.deliveries[evt.OrderID] = true
+			s.mu.Unlock()
+
+			return s.publish(events.DeliveryStartedEvent{
+				BaseEvent:  nextEvent(evt.BaseEvent, events.EventDeliveryStarted),
+				OrderID:    evt.OrderID,
+				DeliveryID: evt.OrderID,
+			})
+		})
+	})
+
+	return s
+}
+
+// startService 서비스별 컨슈머 그룹과 멱등성 저장소를 가진 Router 로 구독 시작
+func (s *sagaFixture) startService(broker *messaging.MemoryBroker, groupID string, register func(r *messaging.Router)) {
+	router := messaging.NewRouter(zap.NewNop(), messaging.WithIdempotency(idempotency.NewMemoryStore(), time.Hour))
+	register(router)
+	runConsumer(s.t, broker.NewConsumer(groupID, zap.NewNop()), router.Topics(), router.Handle)
+}
+
+// createOrder 주문을 생성하고 OrderCreated 발행
+func (s *sagaFixture) createOrder(orderID int64, quantity int) events.OrderCreatedEvent {
+	s.mu.Lock()
+	s.orders[orderID] = "PENDING"
+	s.quantities[orderID] = quantity
+	s.mu.Unlock()
+
+	evt := events.OrderCreatedEvent{
+		BaseEvent: events.BaseEvent{
+			EventID:       uuid.NewString(),
+			EventType:     events.EventOrderCreated,
+			SchemaVersion: 1,
+			OccurredAt:    time.Now(),
+			CorrelationID: uuid.NewString(),
+		},
+		OrderID:  orderID,
+		Amount:   int64(quantity) * 1000,
+		Quantity: quantity,
+	}
+	if err := s.publish(evt); err != nil {
+		s.t.Fatalf("failed to publish order created: %v", err)
+	}
+	return evt
+}
+
+// quantityOf 주문 수량 조회 (재고 서비스가 주문 서비스에서 가져오는 값의 대용)
+func (s *sagaFixture) quantityOf(orderID int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.quantities[orderID]
+}
+
+// publish 주문 ID 를 파티션 키로 이벤트 발행
+func (s *sagaFixture) publish(evt events.Event) error {
+	orderID, ok := events.OrderIDOf(evt)
+	if !ok {
+		return fmt.Errorf("event %T has no order ID", evt)
+	}
+	return s.publisher.Publish(context.Background(), string(evt.GetEventType()), strconv.FormatInt(orderID, 10), evt)
+}
+
+func (s *sagaFixture) setOrder(orderID int64, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders[orderID] = status
+}
+
+func (s *sagaFixture) orderStatus(orderID int64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.orders[orderID]
+}
+
+func (s *sagaFixture) paymentStatus(orderID int64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.payments[orderID]
+}
+
+// nextEvent 원인 이벤트와 같은 SAGA(correlation ID)에 속하는 다음 이벤트 메타데이터
+func nextEvent(cause events.BaseEvent, eventType events.EventType) events.BaseEvent {
+	return events.BaseEvent{
+		EventID:       uuid.NewString(),
+		EventType:     eventType,
+		SchemaVersion: 1,
+		OccurredAt:    time.Now(),
+		CorrelationID: cause.CorrelationID,
+		CausationID:   cause.EventID,
+	}
+}
+
+// runConsumer 구독을 시작하고 테스트가 끝나면 종료 (반환한 함수로 먼저 멈출 수 있음)
+func runConsumer(t *testing.T, consumer messaging.Consumer, topics []string, handler messaging.MessageHandler) func() {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = consumer.Run(ctx, topics, handler)
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+// eventually 조건이 참이 될 때까지 대기 (5초 안에 참이 되지 않으면 실패)
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,260 @@
+package messaging
+
+import (
+	"bufio"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+)
+
+// Metrics 메시징 계측 인터페이스
+//
+// Prometheus, OpenTelemetry 등 각 서비스의 모니터링 백엔드는 이 인터페이스를 구현해 주입한다.
+type Metrics interface {
+	// MessageConsumed 파티션에서 메시지를 수신할 때마다 호출
+	MessageConsumed(topic string, partition int32)
+	// ConsumerLag 파티션 lag (high-water mark - 다음에 읽을 오프셋)
+	ConsumerLag(topic string, partition int32, lag int64)
+	// HandlerObserved 핸들러 실행 시간과 결과 (err 가 nil 이 아니면 실패)
+	HandlerObserved(topic string, duration time.Duration, err error)
+	// MessagePublished 발행 결과 (err 가 nil 이 아니면 실패)
+	MessagePublished(topic string, err error)
+}
+
+// NopMetrics 아무것도 기록하지 않는 기본 구현
+type NopMetrics struct{}
+
+func (NopMetrics) MessageConsumed(string, int32)                {}
+func (NopMetrics) ConsumerLag(string, int32, int64)             {}
+func (NopMetrics) HandlerObserved(string, time.Duration, error) {}
+func (NopMetrics) MessagePublished(string, error)               {}
+
+// DefaultDurationBuckets 핸들러 실행 시간 히스토그램 버킷 (초)
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type resultKey struct {
+	topic  string
+	result string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// MetricsRegistry 메모리 기반 Metrics 구현
+//
+// 별도 의존성 없이 Prometheus 텍스트 형식으로 노출한다 (http.Handler).
+type MetricsRegistry struct {
+	mu        sync.Mutex
+	buckets   []float64
+	consumed  map[partitionKey]uint64
+	lag       map[partitionKey]int64
+	published map[resultKey]uint64
+	errors    map[resultKey]uint64
+	durations map[string]*histogram
+}
+
+var (
+	_ Metrics      = (*MetricsRegistry)(nil)
+	_ http.Handler = (*MetricsRegistry)(nil)
+)
+
+// NewMetricsRegistry 메트릭 저장소 생성
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		buckets:   DefaultDurationBuckets,
+		consumed:  make(map[partitionKey]uint64),
+		lag:       make(map[partitionKey]int64),
+		published: make(map[resultKey]uint64),
+		errors:    make(map[resultKey]uint64),
+		durations: make(map[string]*histogram),
+	}
+}
+
+// MessageConsumed 수신 메시지 수 증가
+func (r *MetricsRegistry) MessageConsumed(topic string, partition int32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consumed[partitionKey{topic, partition}]++
+}
+
+// ConsumerLag 파티션 lag 갱신
+func (r *MetricsRegistry) ConsumerLag(topic string, partition int32, lag int64) {
+	if lag < 0 {
+		lag = 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lag[partitionKey{topic, partition}] = lag
+}
+
+// HandlerObserved 핸들러 실행 시간 히스토그램과 에러 코드별 실패 수 기록
+func (r *MetricsRegistry) HandlerObserved(topic string, duration time.Duration, err error) {
+	seconds := duration.Seconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.durations[topic]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(r.buckets))}
+		r.durations[topic] = h
+	}
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+
+	if err != nil {
+		r.errors[resultKey{topic, errorCode(err)}]++
+	}
+}
+
+// MessagePublished 발행 결과별 메시지 수 증가
+func (r *MetricsRegistry) MessagePublished(topic string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.published[resultKey{topic, result}]++
+}
+
+// TotalLag 모든 파티션 lag 합계
+func (r *MetricsRegistry) TotalLag() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var total int64
+	for _, lag := range r.lag {
+		total += lag
+	}
+	return total
+}
+
+// ServeHTTP Prometheus 텍스트 형식으로 메트릭 출력 (/metrics)
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteTo(w)
+}
+
+// WriteTo Prometheus 텍스트 형식으로 메트릭 기록
+func (r *MetricsRegistry) WriteTo(out io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := &countingWriter{w: bufio.NewWriter(out)}
+
+	writeHeader(w, "messaging_messages_consumed_total", "counter", "Messages received from each partition.")
+	for _, k := range sortedPartitionKeys(r.consumed) {
+		fmt.Fprintf(w, "messaging_messages_consumed_total{topic=%q,partition=\"%d\"} %d\n", k.topic, k.partition, r.consumed[k])
+	}
+
+	writeHeader(w, "messaging_consumer_lag", "gauge", "High-water mark minus the next offset to consume.")
+	for _, k := range sortedPartitionKeys(r.lag) {
+		fmt.Fprintf(w, "messaging_consumer_lag{topic=%q,partition=\"%d\"} %d\n", k.topic, k.partition, r.lag[k])
+	}
+
+	writeHeader(w, "messaging_messages_published_total", "counter", "Messages published by result.")
+	for _, k := range sortedResultKeys(r.published) {
+		fmt.Fprintf(w, "messaging_messages_published_total{topic=%q,result=%q} %d\n", k.topic, k.result, r.published[k])
+	}
+
+	writeHeader(w, "messaging_handler_errors_total", "counter", "Handler failures by error code.")
+	for _, k := range sortedResultKeys(r.errors) {
+		fmt.Fprintf(w, "messaging_handler_errors_total{topic=%q,code=%q} %d\n", k.topic, k.result, r.errors[k])
+	}
+
+	writeHeader(w, "messaging_handler_duration_seconds", "histogram", "Handler execution time.")
+	topics := make([]string, 0, len(r.durations))
+	for topic := range r.durations {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	for _, topic := range topics {
+		h := r.durations[topic]
+		for i, bound := range r.buckets {
+			fmt.Fprintf(w, "messaging_handler_duration_seconds_bucket{topic=%q,le=%q} %d\n",
+				topic, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(w, "messaging_handler_duration_seconds_bucket{topic=%q,le=\"+Inf\"} %d\n", topic, h.count)
+		fmt.Fprintf(w, "messaging_handler_duration_seconds_sum{topic=%q} %g\n", topic, h.sum)
+		fmt.Fprintf(w, "messaging_handler_duration_seconds_count{topic=%q} %d\n", topic, h.count)
+	}
+
+	if err := w.w.Flush(); err != nil {
+		return w.n, err
+	}
+	return w.n, nil
+}
+
+// errorCode 에러의 도메인 에러 코드 (도메인 에러가 아니면 UNKNOWN_ERROR)
+func errorCode(err error) string {
+	var domainErr *errors.DomainError
+	if stderrors.As(err, &domainErr) {
+		return string(domainErr.Code)
+	}
+	return string(errors.ErrCodeUnknownError)
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sortedPartitionKeys[V any](m map[partitionKey]V) []partitionKey {
+	keys := make([]partitionKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].topic != keys[j].topic {
+			return keys[i].topic < keys[j].topic
+		}
+		return keys[i].partition < keys[j].partition
+	})
+	return keys
+}
+
+func sortedResultKeys(m map[resultKey]uint64) []resultKey {
+	keys := make([]resultKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].topic != keys[j].topic {
+			return keys[i].topic < keys[j].topic
+		}
+		return keys[i].result < keys[j].result
+	})
+	return keys
+}
+
+// countingWriter 기록한 바이트 수를 세는 Writer
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
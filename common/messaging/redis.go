@@ -0,0 +1,625 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	_ Publisher = (*RedisPublisher)(nil)
+	_ Consumer  = (*RedisConsumer)(nil)
+)
+
+// HeaderStreamID Redis Streams 엔트리 ID 헤더 키 (수신 메시지에만 설정)
+const HeaderStreamID = "x-stream-id"
+
+// 스트림 엔트리 필드
+const (
+	streamFieldKey     = "key"
+	streamFieldValue   = "value"
+	streamFieldHeaders = "headers"
+)
+
+// RedisStreamsConfig Redis Streams 전송 설정
+//
+// 토픽은 Partitions 개의 스트림(<topic>:<partition>)으로 나뉘고, 같은 키는 항상 같은 스트림에 기록된다.
+// 컨슈머 그룹의 각 스트림은 리스를 가진 인스턴스 하나만 읽으므로 키 단위 순서가 보장된다.
+type RedisStreamsConfig struct {
+	Partitions int
+	// MaxLen 스트림 최대 길이 (근사 trim, 0 이면 trim 하지 않음)
+	MaxLen int64
+	// Block XREADGROUP 대기 시간
+	Block time.Duration
+	// BatchSize 한 번에 읽는 엔트리 수
+	BatchSize int64
+	// ClaimMinIdle 이 시간 이상 ACK 되지 않은 엔트리는 다른 인스턴스가 회수
+	ClaimMinIdle time.Duration
+	// LeaseTTL 파티션 리스 유지 시간 (LeaseTTL/3 마다 갱신)
+	LeaseTTL time.Duration
+	// RetryPolicy 실패한 엔트리를 제자리에서 재시도하는 지연 시간 (모두 실패하면 <topic>.dlq 스트림)
+	RetryPolicy  RetryPolicy
+	DrainTimeout time.Duration
+
+	Codec   Codec
+	Metrics Metrics
+}
+
+// DefaultRedisStreamsConfig 기본 Redis Streams 설정
+func DefaultRedisStreamsConfig() RedisStreamsConfig {
+	return RedisStreamsConfig{
+		Partitions:   4,
+		MaxLen:       100000,
+		Block:        time.Second,
+		BatchSize:    16,
+		ClaimMinIdle: 30 * time.Second,
+		LeaseTTL:     15 * time.Second,
+		RetryPolicy: RetryPolicy{
+			Delays: []time.Duration{time.Second, 5 * time.Second, 30 * time.Second},
+		},
+		DrainTimeout: DefaultDrainTimeout,
+		Codec:        JSONCodec{},
+		Metrics:      NopMetrics{},
+	}
+}
+
+// StreamKey 토픽 파티션의 스트림 키
+func StreamKey(topic string, partition int) string {
+	return fmt.Sprintf("%s:%d", topic, partition)
+}
+
+// streamPartition 키에 해당하는 파티션 (키가 없으면 fallback 사용)
+func streamPartition(key string, partitions int, fallback uint32) int {
+	if key == "" {
+		return int(fallback % uint32(partitions))
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// RedisPublisher Redis Streams 기반 이벤트 발행자
+type RedisPublisher struct {
+	client      redis.UniversalClient
+	config      RedisStreamsConfig
+	serviceName string
+	roundRobin  atomic.Uint32
+}
+
+// NewRedisPublisher Redis Streams 발행자 생성
+func NewRedisPublisher(client redis.UniversalClient, serviceName string, config RedisStreamsConfig) *RedisPublisher {
+	return &RedisPublisher{
+		client:      client,
+		config:      config.withDefaults(),
+		serviceName: serviceName,
+	}
+}
+
+// Publish 이벤트 발행 (이벤트 타입이면 메타데이터 헤더 자동 추가)
+func (p *RedisPublisher) Publish(ctx context.Context, topic string, key string, event interface{}) error {
+	return p.PublishWithHeaders(ctx, topic, key, eventHeaders(event), event)
+}
+
+// PublishWithHeaders 헤더와 함께 이벤트 발행
+func (p *RedisPublisher) PublishWithHeaders(ctx context.Context, topic string, key string, headers Headers, event interface{}) error {
+	payload, contentType, err := encodePayload(p.config.Codec, event)
+	if err != nil {
+		return err
+	}
+
+	headers = headers.clone()
+	headers[HeaderContentType] = contentType
+	if p.serviceName != "" && headers.Get(HeaderProducerService) == "" {
+		headers[HeaderProducerService] = p.serviceName
+	}
+
+	partition := streamPartition(key, p.config.Partitions, p.roundRobin.Add(1))
+	err = xadd(ctx, p.client, StreamKey(topic, partition), p.config.MaxLen, key, payload, headers)
+	p.config.Metrics.MessagePublished(topic, err)
+	return err
+}
+
+// Close 발행자 종료 (클라이언트는 호출자가 관리)
+func (p *RedisPublisher) Close() error {
+	return nil
+}
+
+// xadd 스트림에 엔트리 추가
+func xadd(ctx context.Context, client redis.UniversalClient, stream string, maxLen int64, key string, value []byte, headers Headers) error {
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal headers: %w", err)
+	}
+
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{
+			streamFieldKey:     key,
+			streamFieldValue:   value,
+			streamFieldHeaders: string(encodedHeaders),
+		},
+	}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+
+	if err := client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("failed to add stream entry to %s: %w", stream, err)
+	}
+	return nil
+}
+
+// 리스 갱신/해제 스크립트 (자신이 가진 리스만 변경)
+var (
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisConsumer Redis Streams 컨슈머 그룹 기반 이벤트 구독자
+//
+// 인스턴스들은 파티션 스트림별 리스를 나누어 가지며, 리스를 넘겨받으면 이전 소유자가
+// ACK 하지 못한 엔트리(pending)를 XAUTOCLAIM 으로 회수해 처리한다.
+type RedisConsumer struct {
+	client  redis.UniversalClient
+	groupID string
+	name    string
+	config  RedisStreamsConfig
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	stop    context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewRedisConsumer Redis Streams 구독자 생성 (name 은 그룹 내에서 인스턴스마다 고유해야 함)
+func NewRedisConsumer(client redis.UniversalClient, groupID string, name string, config RedisStreamsConfig, logger *zap.Logger) *RedisConsumer {
+	return &RedisConsumer{
+		client:  client,
+		groupID: groupID,
+		name:    name,
+		config:  config.withDefaults(),
+		logger:  logger,
+	}
+}
+
+// withDefaults 비어 있는 설정값을 기본값으로 채움
+func (c RedisStreamsConfig) withDefaults() RedisStreamsConfig {
+	defaults := DefaultRedisStreamsConfig()
+	if c.Partitions < 1 {
+		c.Partitions = defaults.Partitions
+	}
+	if c.Block <= 0 {
+		c.Block = defaults.Block
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaults.BatchSize
+	}
+	if c.ClaimMinIdle <= 0 {
+		c.ClaimMinIdle = defaults.ClaimMinIdle
+	}
+	if c.LeaseTTL <= 0 {
+		c.LeaseTTL = defaults.LeaseTTL
+	}
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = defaults.DrainTimeout
+	}
+	if c.Codec == nil {
+		c.Codec = defaults.Codec
+	}
+	if c.Metrics == nil {
+		c.Metrics = defaults.Metrics
+	}
+	return c
+}
+
+// Subscribe 토픽 구독 (백그라운드에서 Run 실행, Close 호출 시 종료)
+func (c *RedisConsumer) Subscribe(topics []string, handler MessageHandler) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	c.mu.Lock()
+	if c.stop != nil {
+		c.mu.Unlock()
+		cancel()
+		return fmt.Errorf("consumer is already subscribed")
+	}
+	c.stop = cancel
+	c.stopped = stopped
+	c.mu.Unlock()
+
+	go func() {
+		defer close(stopped)
+		if err := c.Run(ctx, topics, handler); err != nil {
+			c.logger.Error("consumer stopped with error", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// streamPartitionRef 구독 대상 파티션 스트림
+type streamPartitionRef struct {
+	topic     string
+	partition int
+	stream    string
+}
+
+// Run 컨텍스트가 취소될 때까지 토픽을 구독
+//
+// 취소되면 새 엔트리 수신을 멈추고 처리 중인 핸들러를 drain 타임아웃 동안 기다린 뒤 리스를 반납한다.
+func (c *RedisConsumer) Run(ctx context.Context, topics []string, handler MessageHandler) error {
+	var refs []streamPartitionRef
+	for _, topic := range topics {
+		for partition := 0; partition < c.config.Partitions; partition++ {
+			stream := StreamKey(topic, partition)
+			err := c.client.XGroupCreateMkStream(ctx, stream, c.groupID, "0").Err()
+			if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+				return fmt.Errorf("failed to create consumer group for %s: %w", stream, err)
+			}
+			refs = append(refs, streamPartitionRef{topic: topic, partition: partition, stream: stream})
+		}
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+	owned := make(map[string]context.CancelFunc)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(c.config.LeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		c.balance(ctx, refs, owned, func(ref streamPartitionRef, workerCtx context.Context) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.consumePartition(workerCtx, handlerCtx, ref, handler)
+			}()
+		})
+
+		select {
+		case <-ctx.Done():
+			return c.drain(refs, owned, &wg)
+		case <-ticker.C:
+		}
+	}
+}
+
+// balance 리스 갱신, 초과분 반납, 빈 파티션 획득
+//
+// 살아 있는 인스턴스 수로 나눈 몫보다 많은 파티션은 가져가지 않는다.
+func (c *RedisConsumer) balance(
+	ctx context.Context,
+	refs []streamPartitionRef,
+	owned map[string]context.CancelFunc,
+	start func(ref streamPartitionRef, workerCtx context.Context),
+) {
+	members := c.heartbeat(ctx)
+	fairShare := (len(refs) + members - 1) / members
+	ttl := c.config.LeaseTTL.Milliseconds()
+
+	for _, ref := range refs {
+		leaseKey := c.leaseKey(ref.stream)
+
+		if cancel, ok := owned[ref.stream]; ok {
+			renewed, err := renewLeaseScript.Run(ctx, c.client, []string{leaseKey}, c.name, ttl).Int()
+			if err == nil && renewed == 1 && len(owned) <= fairShare {
+				continue
+			}
+
+			// 리스를 잃었거나 몫을 초과하면 반납
+			cancel()
+			delete(owned, ref.stream)
+			if renewed == 1 {
+				releaseLeaseScript.Run(context.WithoutCancel(ctx), c.client, []string{leaseKey}, c.name)
+			}
+			c.logger.Info("stream partition released", zap.String("stream", ref.stream))
+			continue
+		}
+
+		if len(owned) >= fairShare {
+			continue
+		}
+
+		acquired, err := c.client.SetNX(ctx, leaseKey, c.name, c.config.LeaseTTL).Result()
+		if err != nil || !acquired {
+			continue
+		}
+
+		workerCtx, cancel := context.WithCancel(ctx)
+		owned[ref.stream] = cancel
+		c.logger.Info("stream partition assigned", zap.String("stream", ref.stream))
+		start(ref, workerCtx)
+	}
+}
+
+// heartbeat 그룹 멤버 목록에 자신을 기록하고 살아 있는 멤버 수 반환
+func (c *RedisConsumer) heartbeat(ctx context.Context) int {
+	key := fmt.Sprintf("%s:members", c.groupID)
+	now := time.Now()
+
+	pipe := c.client.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: c.name})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%d", now.Add(-c.config.LeaseTTL).UnixMilli()))
+	count := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil || count.Val() < 1 {
+		return 1
+	}
+	return int(count.Val())
+}
+
+func (c *RedisConsumer) leaseKey(stream string) string {
+	return fmt.Sprintf("%s:lease:%s", stream, c.groupID)
+}
+
+// drain 처리 중인 핸들러를 기다린 뒤 리스와 멤버십 반납
+func (c *RedisConsumer) drain(refs []streamPartitionRef, owned map[string]context.CancelFunc, wg *sync.WaitGroup) error {
+	c.logger.Info("draining consumer", zap.Duration("timeout", c.config.DrainTimeout))
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+		c.logger.Info("consumer drained")
+	case <-time.After(c.config.DrainTimeout):
+		err = fmt.Errorf("consumer drain timed out after %s", c.config.DrainTimeout)
+	}
+
+	ctx := context.Background()
+	for stream := range owned {
+		releaseLeaseScript.Run(ctx, c.client, []string{c.leaseKey(stream)}, c.name)
+	}
+	c.client.ZRem(ctx, fmt.Sprintf("%s:members", c.groupID), c.name)
+
+	return err
+}
+
+// consumePartition 리스를 가진 동안 파티션 스트림을 순서대로 처리
+func (c *RedisConsumer) consumePartition(ctx, handlerCtx context.Context, ref streamPartitionRef, handler MessageHandler) {
+	var lastClaim time.Time
+
+	for ctx.Err() == nil {
+		// 이전 소유자나 중단된 처리가 남긴 pending 엔트리 회수
+		if time.Since(lastClaim) >= c.config.ClaimMinIdle {
+			lastClaim = time.Now()
+			if !c.reclaim(ctx, handlerCtx, ref, handler) {
+				return
+			}
+		}
+
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.groupID,
+			Consumer: c.name,
+			Streams:  []string{ref.stream, ">"},
+			Count:    c.config.BatchSize,
+			Block:    c.config.Block,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Error("failed to read stream", zap.String("stream", ref.stream), zap.Error(err))
+			_ = waitUntil(ctx, time.Now().Add(time.Second))
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, entry := range stream.Messages {
+				if !c.process(ctx, handlerCtx, ref, entry, handler) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// reclaim ClaimMinIdle 이상 ACK 되지 않은 엔트리를 가져와 처리
+func (c *RedisConsumer) reclaim(ctx, handlerCtx context.Context, ref streamPartitionRef, handler MessageHandler) bool {
+	start := "0-0"
+	for {
+		entries, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   ref.stream,
+			Group:    c.groupID,
+			Consumer: c.name,
+			MinIdle:  c.config.ClaimMinIdle,
+			Start:    start,
+			Count:    c.config.BatchSize,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Error("failed to claim pending entries", zap.String("stream", ref.stream), zap.Error(err))
+			}
+			return ctx.Err() == nil
+		}
+
+		if len(entries) > 0 {
+			c.logger.Warn("reclaimed pending entries",
+				zap.String("stream", ref.stream),
+				zap.Int("count", len(entries)))
+		}
+		for _, entry := range entries {
+			if !c.process(ctx, handlerCtx, ref, entry, handler) {
+				return false
+			}
+		}
+
+		if next == "0-0" || next == "" {
+			return true
+		}
+		start = next
+	}
+}
+
+// process 엔트리 하나를 처리하고 ACK
+//
+// 실패하면 RetryPolicy 지연만큼 기다렸다가 같은 엔트리를 다시 처리해 키 순서를 유지하고,
+// 모두 실패하거나 영구 실패면 <topic>.dlq 스트림으로 옮긴다.
+// 대기 중 컨텍스트가 취소되면 ACK 하지 않고 false 를 반환한다 (다음 소유자가 회수).
+func (c *RedisConsumer) process(ctx, handlerCtx context.Context, ref streamPartitionRef, entry redis.XMessage, handler MessageHandler) bool {
+	msg := decodeStreamEntry(ref, entry)
+	c.config.Metrics.MessageConsumed(ref.topic, int32(ref.partition))
+
+	c.logger.Info("message received",
+		zap.String("stream", ref.stream),
+		zap.String("id", entry.ID),
+		zap.String("key", string(msg.Key)),
+		zap.String("eventType", string(msg.EventType())),
+		zap.String("eventId", msg.EventID()),
+		zap.String("correlationId", msg.CorrelationID()))
+
+	attempt := 0
+	for {
+		delivered := *msg
+		start := time.Now()
+		err := handler(handlerCtx, &delivered)
+		c.config.Metrics.HandlerObserved(ref.topic, time.Since(start), err)
+
+		if err == nil {
+			break
+		}
+
+		if delay, ok := backpressureDelay(err); ok {
+			c.logger.Warn("handler requested backpressure, pausing stream",
+				zap.String("stream", ref.stream),
+				zap.Duration("delay", delay),
+				zap.Error(err))
+			if waitUntil(ctx, time.Now().Add(delay)) != nil {
+				return false
+			}
+			continue
+		}
+
+		if isPermanentFailure(err) || attempt >= len(c.config.RetryPolicy.Delays) {
+			if dlqErr := c.deadLetter(handlerCtx, ref, entry.ID, msg, attempt+1, err); dlqErr != nil {
+				c.logger.Error("failed to move entry to dead letter stream", zap.Error(dlqErr))
+				return false
+			}
+			break
+		}
+
+		delay := c.config.RetryPolicy.Delays[attempt]
+		attempt++
+		c.logger.Warn("message scheduled for retry",
+			zap.String("stream", ref.stream),
+			zap.String("id", entry.ID),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		if waitUntil(ctx, time.Now().Add(delay)) != nil {
+			return false
+		}
+	}
+
+	if err := c.client.XAck(handlerCtx, ref.stream, c.groupID, entry.ID).Err(); err != nil {
+		c.logger.Error("failed to ack stream entry",
+			zap.String("stream", ref.stream),
+			zap.String("id", entry.ID),
+			zap.Error(err))
+	}
+	return true
+}
+
+// deadLetter 실패한 엔트리를 에러 헤더와 함께 <topic>.dlq 스트림에 기록
+func (c *RedisConsumer) deadLetter(ctx context.Context, ref streamPartitionRef, id string, msg *Message, attempt int, handlerErr error) error {
+	headers := msg.Headers.clone()
+	delete(headers, HeaderStreamID)
+	headers[HeaderOriginalTopic] = ref.topic
+	headers[HeaderOriginalPartition] = fmt.Sprintf("%d", ref.partition)
+	headers[HeaderOriginalOffset] = id
+	headers[HeaderRetryAttempt] = fmt.Sprintf("%d", attempt)
+	headers[HeaderError] = handlerErr.Error()
+
+	c.logger.Error("message sent to dead letter queue",
+		zap.String("stream", ref.stream),
+		zap.String("id", id),
+		zap.Error(handlerErr))
+
+	return xadd(ctx, c.client, DLQTopic(ref.topic), c.config.MaxLen, string(msg.Key), msg.Value, headers)
+}
+
+// decodeStreamEntry 스트림 엔트리를 Message 로 변환
+func decodeStreamEntry(ref streamPartitionRef, entry redis.XMessage) *Message {
+	msg := &Message{
+		Topic:     ref.topic,
+		Partition: int32(ref.partition),
+		Offset:    streamOffset(entry.ID),
+		Headers:   Headers{},
+	}
+	if key, ok := entry.Values[streamFieldKey].(string); ok {
+		msg.Key = []byte(key)
+	}
+	if value, ok := entry.Values[streamFieldValue].(string); ok {
+		msg.Value = []byte(value)
+	}
+	if encoded, ok := entry.Values[streamFieldHeaders].(string); ok {
+		_ = json.Unmarshal([]byte(encoded), &msg.Headers)
+	}
+	msg.Headers[HeaderStreamID] = entry.ID
+	return msg
+}
+
+// streamSeqBits 스트림 ID 를 오프셋으로 바꿀 때 시퀀스에 쓰는 비트 수
+const streamSeqBits = 20
+
+// streamOffset 스트림 엔트리 ID("<ms>-<seq>")를 단조 증가하는 오프셋으로 변환
+//
+// 상위 비트는 밀리초, 하위 20비트는 시퀀스이며 같은 밀리초에 2^20 개를 넘는 엔트리는 마지막 값으로 고정된다.
+// 로그와 지표용이며, 메시지 위치를 정확히 식별할 때는 Message.Position(엔트리 ID)을 사용한다.
+func streamOffset(id string) int64 {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0
+	}
+	millis, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil || millis >= 1<<(63-streamSeqBits) {
+		return 0
+	}
+	sequence, err := strconv.ParseInt(seq, 10, 64)
+	if err != nil {
+		return 0
+	}
+	if sequence >= 1<<streamSeqBits {
+		sequence = 1<<streamSeqBits - 1
+	}
+	return millis<<streamSeqBits | sequence
+}
+
+// Close 구독자 종료 (Subscribe로 시작한 구독은 drain 후 종료, 클라이언트는 호출자가 관리)
+func (c *RedisConsumer) Close() error {
+	c.mu.Lock()
+	stop, stopped := c.stop, c.stopped
+	c.stop, c.stopped = nil, nil
+	c.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-stopped
+	}
+	return nil
+}
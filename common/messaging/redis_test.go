@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStreamOffsetIsMonotonic(t *testing.T) {
+	ids := []string{
+		"0-1",
+		"1526919030474-0",
+		"1526919030474-1",
+		"1526919030474-1048575",
+		"1526919030475-0",
+		"1735689600000-3",
+	}
+
+	var previous int64 = -1
+	for _, id := range ids {
+		offset := streamOffset(id)
+		if offset <= previous {
+			t.Fatalf("expected offset of %s (%d) to be greater than %d", id, offset, previous)
+		}
+		previous = offset
+	}
+}
+
+func TestStreamOffsetSaturatesSequence(t *testing.T) {
+	// 같은 밀리초의 시퀀스가 하위 비트를 넘어도 다음 밀리초를 앞지르지 않음
+	if streamOffset("1000-2000000") >= streamOffset("1001-0") {
+		t.Fatal("expected saturated sequence to stay below the next millisecond")
+	}
+}
+
+func TestStreamOffsetInvalid(t *testing.T) {
+	for _, id := range []string{"", "abc", "1-x", "x-1", "9223372036854775807-0"} {
+		if offset := streamOffset(id); offset != 0 {
+			t.Fatalf("expected offset 0 for %q, got %d", id, offset)
+		}
+	}
+}
+
+func TestDecodeStreamEntryPosition(t *testing.T) {
+	ref := streamPartitionRef{topic: "order.created.v1", partition: 2}
+	first := decodeStreamEntry(ref, redis.XMessage{ID: "1526919030474-0", Values: map[string]interface{}{streamFieldValue: "{}"}})
+	second := decodeStreamEntry(ref, redis.XMessage{ID: "1526919030474-1", Values: map[string]interface{}{streamFieldValue: "{}"}})
+
+	if first.Offset == 0 || second.Offset <= first.Offset {
+		t.Fatalf("expected increasing non-zero offsets, got %d and %d", first.Offset, second.Offset)
+	}
+	if first.Position() != "1526919030474-0" || second.Position() != "1526919030474-1" {
+		t.Fatalf("expected stream IDs as positions, got %s and %s", first.Position(), second.Position())
+	}
+	if first.Partition != 2 || first.Topic != "order.created.v1" {
+		t.Fatalf("unexpected message %+v", first)
+	}
+}
@@ -0,0 +1,134 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+)
+
+// 재시도/DLQ 메시지에 붙는 헤더 키
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderRetryAttempt      = "x-retry-attempt"
+	HeaderRetryNotBefore    = "x-retry-not-before"
+	HeaderError             = "x-error"
+)
+
+// RetryPolicy 핸들러 실패 시 재시도 토픽/DLQ 라우팅 정책
+type RetryPolicy struct {
+	// Delays 재시도 티어별 지연 시간 (i번째 값이 <topic>.retry.<i+1> 의 지연)
+	Delays []time.Duration
+}
+
+// DefaultRetryPolicy 기본 재시도 정책 (10초, 1분, 10분 후 DLQ)
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Delays: []time.Duration{10 * time.Second, time.Minute, 10 * time.Minute},
+	}
+}
+
+// RetryTopic 재시도 티어 토픽 이름 (tier는 1부터 시작)
+func RetryTopic(topic string, tier int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, tier)
+}
+
+// DLQTopic Dead Letter Queue 토픽 이름
+func DLQTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+// retryTopics 원본 토픽에 대응하는 모든 재시도 토픽
+func (p RetryPolicy) retryTopics(topics []string) []string {
+	result := make([]string, 0, len(topics)*len(p.Delays))
+	for _, topic := range topics {
+		for tier := 1; tier <= len(p.Delays); tier++ {
+			result = append(result, RetryTopic(topic, tier))
+		}
+	}
+	return result
+}
+
+// isPermanentFailure 재시도해도 결과가 바뀌지 않는 에러인지 판단
+//
+// 비즈니스 에러와 역직렬화 에러는 바로 DLQ로 보내고, 재시도 가능한 기술 에러와
+// 분류되지 않은 에러는 재시도 티어를 거친다.
+func isPermanentFailure(err error) bool {
+	if errors.IsRetryable(err) {
+		return false
+	}
+	return errors.IsBusinessError(err) || errors.IsCode(err, errors.ErrCodeSerializationError)
+}
+
+// retryRoute 실패한 메시지가 이동할 토픽 결정
+func (p RetryPolicy) retryRoute(originalTopic string, attempt int, err error) (topic string, delay time.Duration, dlq bool) {
+	if isPermanentFailure(err) || attempt > len(p.Delays) {
+		return DLQTopic(originalTopic), 0, true
+	}
+	return RetryTopic(originalTopic, attempt), p.Delays[attempt-1], false
+}
+
+// retryState 수신한 메시지의 재시도 메타데이터
+type retryState struct {
+	originalTopic string
+	attempt       int
+	notBefore     time.Time
+}
+
+// parseRetryState 헤더에서 재시도 메타데이터 추출 (재시도 토픽이 아니면 원본 토픽 기준)
+func parseRetryState(message *sarama.ConsumerMessage) retryState {
+	state := retryState{originalTopic: message.Topic}
+	for _, header := range message.Headers {
+		switch string(header.Key) {
+		case HeaderOriginalTopic:
+			state.originalTopic = string(header.Value)
+		case HeaderRetryAttempt:
+			if attempt, err := strconv.Atoi(string(header.Value)); err == nil {
+				state.attempt = attempt
+			}
+		case HeaderRetryNotBefore:
+			if ms, err := strconv.ParseInt(string(header.Value), 10, 64); err == nil {
+				state.notBefore = time.UnixMilli(ms)
+			}
+		}
+	}
+	return state
+}
+
+// withHeaders 원본 헤더를 유지하면서 지정한 헤더를 덮어쓴 헤더 목록 생성
+func withHeaders(original []*sarama.RecordHeader, overrides map[string]string) []sarama.RecordHeader {
+	headers := make([]sarama.RecordHeader, 0, len(original)+len(overrides))
+	for _, header := range original {
+		if _, ok := overrides[string(header.Key)]; ok {
+			continue
+		}
+		headers = append(headers, *header)
+	}
+	for key, value := range overrides {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	return headers
+}
+
+// waitUntil 지정한 시각까지 대기 (컨텍스트 취소 시 즉시 반환)
+func waitUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,229 @@
+package messaging
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"go.uber.org/zap"
+)
+
+// Router 이벤트 타입별로 타입이 지정된 핸들러를 호출하는 라우터
+//
+// 디코딩, 멱등성 체크, 로깅, 알 수 없는 이벤트 처리를 한 곳에서 담당한다.
+type Router struct {
+	routes    map[events.EventType]MessageHandler
+	codecs    *CodecRegistry
+	logger    *zap.Logger
+	unknown   MessageHandler
+	idem      idempotency.Store
+	idemTTL   time.Duration
+	idemLease time.Duration
+	poison    Quarantine
+}
+
+// DefaultIdempotencyLease 핸들러 실행 중 이벤트 ID 점유를 유지하는 기본 시간
+//
+// 처리자가 죽으면 이 시간이 지난 뒤 재전달된 메시지가 점유를 넘겨받는다. 핸들러가 이보다 오래 걸리면
+// 다른 처리자가 같은 이벤트를 처리할 수 있으므로 WithIdempotencyLease 로 늘린다.
+const DefaultIdempotencyLease = 30 * time.Second
+
+// Quarantine 디코딩할 수 없는 메시지(poison message) 격리 저장소
+//
+// 격리된 메시지는 원본 바이트와 위치(토픽/파티션/오프셋), 디코딩 에러와 함께 보관되어
+// 운영자가 확인하고 수정한 뒤 다시 발행할 수 있다.
+type Quarantine interface {
+	Quarantine(ctx context.Context, msg *Message, reason error) error
+}
+
+// RouterOption 라우터 옵션
+type RouterOption func(*Router)
+
+// WithIdempotency 이벤트 ID 기준 중복 처리 방지 (처리 완료 표시를 ttl 동안 보관)
+func WithIdempotency(store idempotency.Store, ttl time.Duration) RouterOption {
+	return func(r *Router) {
+		r.idem = store
+		r.idemTTL = ttl
+	}
+}
+
+// WithIdempotencyLease 핸들러 실행 중 이벤트 ID 점유 시간 (기본값 DefaultIdempotencyLease)
+func WithIdempotencyLease(lease time.Duration) RouterOption {
+	return func(r *Router) {
+		r.idemLease = lease
+	}
+}
+
+// WithQuarantine 디코딩 실패 메시지를 격리 저장소에 보관하고 정상 처리로 간주
+//
+// 격리 저장에 실패하면 디코딩 에러를 그대로 반환해 컨슈머의 DLQ 경로로 넘긴다.
+func WithQuarantine(q Quarantine) RouterOption {
+	return func(r *Router) {
+		r.poison = q
+	}
+}
+
+// WithRouterCodecs 디코딩에 사용할 코덱 저장소 지정 (기본값 DefaultCodecs)
+func WithRouterCodecs(codecs *CodecRegistry) RouterOption {
+	return func(r *Router) {
+		r.codecs = codecs
+	}
+}
+
+// WithUnknownEventHandler 등록되지 않은 이벤트 타입 처리 핸들러 (기본값: 경고 로그 후 무시)
+func WithUnknownEventHandler(handler MessageHandler) RouterOption {
+	return func(r *Router) {
+		r.unknown = handler
+	}
+}
+
+// NewRouter 라우터 생성
+func NewRouter(logger *zap.Logger, opts ...RouterOption) *Router {
+	r := &Router{
+		routes:    make(map[events.EventType]MessageHandler),
+		codecs:    DefaultCodecs,
+		logger:    logger,
+		idemLease: DefaultIdempotencyLease,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// On 이벤트 구조체 타입 T 에 대한 핸들러 등록
+//
+//	messaging.On[events.PaymentCompletedEvent](router, orderService.HandlePaymentCompleted)
+func On[T events.Event](r *Router, handler TypedHandler[T]) {
+	var zero T
+	eventType := events.TypeOf(zero)
+	if eventType == "" {
+		panic(fmt.Sprintf("messaging: cannot resolve event type for %T", zero))
+	}
+	if _, exists := r.routes[eventType]; exists {
+		panic(fmt.Sprintf("messaging: duplicate handler for %s", eventType))
+	}
+
+	r.routes[eventType] = func(ctx context.Context, msg *Message) error {
+		var evt T
+		if err := r.codecs.Decode(msg, &evt); err != nil {
+			return r.quarantine(ctx, msg, err)
+		}
+		return r.handleOnce(ctx, evt.GetEventID(), func(ctx context.Context) error {
+			return handler(ctx, evt)
+		})
+	}
+}
+
+// Topics 등록된 이벤트 타입 목록 (구독할 토픽)
+func (r *Router) Topics() []string {
+	topics := make([]string, 0, len(r.routes))
+	for eventType := range r.routes {
+		topics = append(topics, string(eventType))
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Handle 메시지를 이벤트 타입에 맞는 핸들러로 전달 (MessageHandler 로 사용)
+func (r *Router) Handle(ctx context.Context, msg *Message) error {
+	eventType := msg.EventType()
+
+	// 수신 로그는 컨슈머가 남기므로 여기서는 처리 결과만 기록
+	handler, ok := r.routes[eventType]
+	if !ok {
+		if r.unknown != nil {
+			return r.unknown(ctx, msg)
+		}
+		r.logger.Warn("unknown event type",
+			zap.String("topic", msg.Topic),
+			zap.String("eventType", string(eventType)))
+		return nil
+	}
+
+	start := time.Now()
+	if err := handler(ctx, msg); err != nil {
+		r.logger.Error("event handler failed",
+			zap.String("eventType", string(eventType)),
+			zap.String("eventId", msg.EventID()),
+			zap.Duration("elapsed", time.Since(start)),
+			zap.Error(err))
+		return err
+	}
+
+	r.logger.Info("event handled",
+		zap.String("eventType", string(eventType)),
+		zap.String("eventId", msg.EventID()),
+		zap.Duration("elapsed", time.Since(start)))
+	return nil
+}
+
+// quarantine 격리 저장소가 있으면 디코딩 실패 메시지를 보관 (없으면 에러 반환)
+func (r *Router) quarantine(ctx context.Context, msg *Message, decodeErr error) error {
+	if r.poison == nil {
+		return decodeErr
+	}
+
+	if err := r.poison.Quarantine(ctx, msg, decodeErr); err != nil {
+		r.logger.Error("failed to quarantine message",
+			zap.String("topic", msg.Topic),
+			zap.Int32("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err))
+		return decodeErr
+	}
+
+	r.logger.Warn("message quarantined",
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.Error(decodeErr))
+	return nil
+}
+
+// handleOnce 멱등성 저장소가 있으면 이벤트 ID 를 점유한 뒤 처리하고 완료 표시
+//
+// 이미 완료된 이벤트는 건너뛰고, 다른 처리자가 처리 중이면 ErrInProgress 를 반환해 나중에 다시 전달받는다.
+// 핸들러가 실패하면 점유를 풀어 재시도가 바로 넘겨받게 한다.
+func (r *Router) handleOnce(ctx context.Context, eventID string, fn func(ctx context.Context) error) error {
+	if r.idem == nil || eventID == "" {
+		return fn(ctx)
+	}
+
+	claim, err := r.idem.Claim(ctx, eventID, r.idemLease)
+	if err != nil {
+		return err
+	}
+	if !claim.Acquired {
+		if claim.State == idempotency.StateCompleted {
+			r.logger.Info("event already processed", zap.String("eventId", eventID))
+			return nil
+		}
+		return fmt.Errorf("event %s: %w", eventID, idempotency.ErrInProgress)
+	}
+
+	if err := fn(idempotency.WithClaim(ctx, claim)); err != nil {
+		// 재시도가 lease 만료를 기다리지 않도록 점유 해제 (종료 중이어도 해제되도록 취소되지 않는 컨텍스트 사용)
+		if releaseErr := r.idem.Release(context.WithoutCancel(ctx), eventID, claim.Token); releaseErr != nil {
+			r.logger.Warn("failed to release idempotency claim", zap.String("eventId", eventID), zap.Error(releaseErr))
+		}
+		return err
+	}
+
+	// 부수 효과는 이미 반영되었으므로 완료 표시에 실패해도 에러를 반환하지 않음 (재처리 시 다시 점유)
+	if err := r.idem.Complete(context.WithoutCancel(ctx), eventID, claim.Token, nil, r.idemTTL); err != nil {
+		if stderrors.Is(err, idempotency.ErrClaimLost) {
+			r.logger.Warn("idempotency claim expired before completion, handler exceeded lease",
+				zap.String("eventId", eventID),
+				zap.Int64("token", claim.Token),
+				zap.Duration("lease", r.idemLease))
+			return nil
+		}
+		r.logger.Warn("failed to mark event as processed", zap.String("eventId", eventID), zap.Error(err))
+	}
+	return nil
+}
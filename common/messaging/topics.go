@@ -0,0 +1,236 @@
+package messaging
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"go.uber.org/zap"
+)
+
+// 토픽 cleanup.policy
+const (
+	CleanupDelete  = "delete"
+	CleanupCompact = "compact"
+)
+
+// TopicSpec 토픽 선언 (파티션, 복제 계수, 보존 기간, cleanup 정책)
+type TopicSpec struct {
+	Name              string
+	Partitions        int32
+	ReplicationFactor int16
+	// Retention 메시지 보존 기간 (0 이면 브로커 기본값, 음수면 무제한)
+	Retention     time.Duration
+	CleanupPolicy string
+}
+
+// DLQRetention DLQ 토픽 보존 기간 (운영자가 확인하고 재처리할 시간)
+const DLQRetention = 30 * 24 * time.Hour
+
+// eventTopics 이벤트 타입별 토픽 선언 (복제 계수는 클러스터에 맞게 EventTopicSpecs 에서 지정)
+//
+// 같은 주문의 이벤트 순서는 파티션 키(주문 ID)로 보장되므로, 파티션 수를 줄이거나
+// 바꾸면 순서가 깨진다. 파티션을 늘릴 때는 컨슈머를 멈추고 진행해야 한다.
+var eventTopics = map[events.EventType]TopicSpec{
+	events.EventOrderCreated:   {Partitions: 6, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventOrderCompleted: {Partitions: 6, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventOrderCanceled:  {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventOrderFailed:    {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+
+	events.EventPaymentCompleted: {Partitions: 6, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventPaymentFailed:    {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventPaymentRefunded:  {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+
+	events.EventStockReserved:          {Partitions: 6, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventStockReservationFailed: {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventStockRestored:          {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+
+	events.EventDeliveryStarted: {Partitions: 6, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+	events.EventDeliveryFailed:  {Partitions: 3, Retention: 7 * 24 * time.Hour, CleanupPolicy: CleanupDelete},
+}
+
+// EventTopicSpec 이벤트 타입의 토픽 선언
+func EventTopicSpec(eventType events.EventType, replicationFactor int16) (TopicSpec, bool) {
+	spec, ok := eventTopics[eventType]
+	if !ok {
+		return TopicSpec{}, false
+	}
+	spec.Name = string(eventType)
+	spec.ReplicationFactor = replicationFactor
+	return spec, true
+}
+
+// EventTopicSpecs 모든 이벤트 타입의 토픽 선언
+func EventTopicSpecs(replicationFactor int16) []TopicSpec {
+	specs := make([]TopicSpec, 0, len(eventTopics))
+	for _, eventType := range events.AllEventTypes() {
+		spec, ok := EventTopicSpec(eventType, replicationFactor)
+		if !ok {
+			panic(fmt.Sprintf("messaging: no topic spec declared for %s", eventType))
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// RetryTopicSpecs 구독 토픽의 재시도 티어와 DLQ 토픽 선언
+//
+// 재시도 토픽은 원본과 같은 파티션 수를 사용해 키 순서를 유지한다.
+func RetryTopicSpecs(topics []string, policy RetryPolicy, replicationFactor int16) []TopicSpec {
+	specs := make([]TopicSpec, 0, len(topics)*(len(policy.Delays)+1))
+	for _, topic := range topics {
+		base, ok := EventTopicSpec(events.EventType(topic), replicationFactor)
+		if !ok {
+			continue
+		}
+		for tier := 1; tier <= len(policy.Delays); tier++ {
+			spec := base
+			spec.Name = RetryTopic(topic, tier)
+			specs = append(specs, spec)
+		}
+		dlq := base
+		dlq.Name = DLQTopic(topic)
+		dlq.Retention = DLQRetention
+		specs = append(specs, dlq)
+	}
+	return specs
+}
+
+// TopicAdmin 토픽 생성과 설정 검증
+type TopicAdmin struct {
+	admin  sarama.ClusterAdmin
+	logger *zap.Logger
+}
+
+// NewTopicAdmin 토픽 관리자 생성
+func NewTopicAdmin(brokers []string, logger *zap.Logger) (*TopicAdmin, error) {
+	config := sarama.NewConfig()
+	admin, err := sarama.NewClusterAdmin(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka cluster admin: %w", err)
+	}
+	return &TopicAdmin{admin: admin, logger: logger}, nil
+}
+
+// TopicDriftError 이미 있는 토픽의 설정이 선언과 다름 (토픽은 변경하지 않음)
+type TopicDriftError struct {
+	Drifts []string
+}
+
+func (e *TopicDriftError) Error() string {
+	return fmt.Sprintf("topic configuration drift: %s", strings.Join(e.Drifts, "; "))
+}
+
+// Ensure 없는 토픽은 생성하고, 이미 있는 토픽은 선언과 설정이 같은지 검증
+//
+// 설정이 다른 토픽이 있으면 변경하지 않고 모든 차이를 담은 *TopicDriftError 를 반환한다.
+// 브로커 기본값으로 자동 생성된 기존 토픽도 차이로 보고되므로, 서비스는 기본적으로 경고만 남기고
+// 운영자가 토픽을 맞춘 뒤 엄격 모드에서만 기동을 중단한다.
+func (a *TopicAdmin) Ensure(ctx context.Context, specs []TopicSpec) error {
+	existing, err := a.admin.ListTopics()
+	if err != nil {
+		return fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	var drifts []string
+	for _, spec := range specs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		detail, ok := existing[spec.Name]
+		if !ok {
+			if err := a.create(spec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		drifts = append(drifts, topicDrift(spec, detail)...)
+	}
+
+	if len(drifts) > 0 {
+		return &TopicDriftError{Drifts: drifts}
+	}
+	return nil
+}
+
+// Close 관리자 연결 종료
+func (a *TopicAdmin) Close() error {
+	return a.admin.Close()
+}
+
+func (a *TopicAdmin) create(spec TopicSpec) error {
+	err := a.admin.CreateTopic(spec.Name, &sarama.TopicDetail{
+		NumPartitions:     spec.Partitions,
+		ReplicationFactor: spec.ReplicationFactor,
+		ConfigEntries:     spec.configEntries(),
+	}, false)
+
+	// 다른 인스턴스가 동시에 생성한 경우
+	var topicErr *sarama.TopicError
+	if stderrors.As(err, &topicErr) && topicErr.Err == sarama.ErrTopicAlreadyExists {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create topic %s: %w", spec.Name, err)
+	}
+
+	a.logger.Info("topic created",
+		zap.String("topic", spec.Name),
+		zap.Int32("partitions", spec.Partitions),
+		zap.Int16("replicationFactor", spec.ReplicationFactor))
+	return nil
+}
+
+// configEntries 토픽 생성 시 지정할 설정
+func (s TopicSpec) configEntries() map[string]*string {
+	entries := make(map[string]*string)
+	if s.Retention != 0 {
+		retention := s.retentionMs()
+		entries["retention.ms"] = &retention
+	}
+	if s.CleanupPolicy != "" {
+		policy := s.CleanupPolicy
+		entries["cleanup.policy"] = &policy
+	}
+	return entries
+}
+
+func (s TopicSpec) retentionMs() string {
+	if s.Retention < 0 {
+		return "-1"
+	}
+	return strconv.FormatInt(s.Retention.Milliseconds(), 10)
+}
+
+// topicDrift 선언과 실제 토픽 설정의 차이
+func topicDrift(spec TopicSpec, detail sarama.TopicDetail) []string {
+	var drifts []string
+
+	if detail.NumPartitions != spec.Partitions {
+		drifts = append(drifts, fmt.Sprintf("%s partitions=%d (declared %d)",
+			spec.Name, detail.NumPartitions, spec.Partitions))
+	}
+	if detail.ReplicationFactor != spec.ReplicationFactor {
+		drifts = append(drifts, fmt.Sprintf("%s replication.factor=%d (declared %d)",
+			spec.Name, detail.ReplicationFactor, spec.ReplicationFactor))
+	}
+
+	for name, declared := range spec.configEntries() {
+		actual := "<broker default>"
+		if value, ok := detail.ConfigEntries[name]; ok && value != nil {
+			actual = *value
+		}
+		if actual != *declared {
+			drifts = append(drifts, fmt.Sprintf("%s %s=%s (declared %s)", spec.Name, name, actual, *declared))
+		}
+	}
+
+	return drifts
+}
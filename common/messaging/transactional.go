@@ -0,0 +1,293 @@
+package messaging
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// TransformHandler 수신한 메시지를 처리하고 결과 이벤트를 out 으로 발행하는 핸들러
+//
+// out 으로 발행한 메시지와 수신 메시지의 오프셋 커밋은 하나의 Kafka 트랜잭션으로 묶인다.
+type TransformHandler func(ctx context.Context, msg *Message, out Publisher) error
+
+// TransactionalProcessor Kafka 트랜잭션 기반 consume-transform-produce 처리기
+//
+// Outbox 테이블 없이 상태를 갖지 않는 SAGA 참여자를 exactly-once 로 실행할 때 사용한다.
+// 처리 결과 발행과 오프셋 커밋이 원자적으로 이루어지며, 다운스트림은 read-committed 로 구독해야 한다.
+//
+// sarama 는 컨슈머 그룹 메타데이터 기반 펜싱(KIP-447)을 지원하지 않으므로, 할당받은 파티션마다
+// transactional.id 가 "<groupID>-<topic>-<partition>" 인 프로듀서를 따로 만든다.
+// 리밸런싱으로 파티션을 넘겨받은 인스턴스가 같은 ID 로 프로듀서를 초기화하면 이전 소유자는 펜싱되고
+// 커밋되지 않은 트랜잭션은 중단된다. 파티션마다 프로듀서가 있으므로 파티션 간 처리는 병렬로 진행된다.
+type TransactionalProcessor struct {
+	groupID       string
+	consumerGroup sarama.ConsumerGroup
+	logger        *zap.Logger
+	serviceName   string
+	codec         Codec
+	retryBackoff  time.Duration
+
+	// newProducer transactional.id 로 파티션 전용 프로듀서 생성
+	newProducer func(transactionalID string) (sarama.SyncProducer, error)
+}
+
+// NewTransactionalProcessor 트랜잭션 처리기 생성
+//
+// 트랜잭션 프로듀서는 파티션을 할당받을 때 만들어지므로 생성 시점에는 컨슈머 그룹만 연결한다.
+func NewTransactionalProcessor(
+	brokers []string,
+	groupID string,
+	serviceName string,
+	logger *zap.Logger,
+) (*TransactionalProcessor, error) {
+	consumerConfig := sarama.NewConfig()
+	consumerConfig.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
+	consumerConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	consumerConfig.Consumer.Offsets.AutoCommit.Enable = false
+	consumerConfig.Consumer.IsolationLevel = sarama.ReadCommitted
+	consumerConfig.Consumer.Return.Errors = true
+
+	consumerGroup, err := sarama.NewConsumerGroup(brokers, groupID, consumerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	return &TransactionalProcessor{
+		groupID:       groupID,
+		consumerGroup: consumerGroup,
+		logger:        logger,
+		serviceName:   serviceName,
+		codec:         JSONCodec{},
+		retryBackoff:  time.Second,
+		newProducer: func(transactionalID string) (sarama.SyncProducer, error) {
+			return sarama.NewSyncProducer(brokers, transactionalProducerConfig(transactionalID))
+		},
+	}, nil
+}
+
+// transactionalProducerConfig 트랜잭션 프로듀서 설정
+func transactionalProducerConfig(transactionalID string) *sarama.Config {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Idempotent = true
+	config.Producer.Transaction.ID = transactionalID
+	config.Net.MaxOpenRequests = 1
+	return config
+}
+
+// transactionalID 파티션 전용 프로듀서의 transactional.id
+func (p *TransactionalProcessor) transactionalID(topic string, partition int32) string {
+	return fmt.Sprintf("%s-%s-%d", p.groupID, topic, partition)
+}
+
+// Run 컨텍스트가 취소될 때까지 토픽을 구독하며 메시지마다 트랜잭션을 실행
+func (p *TransactionalProcessor) Run(ctx context.Context, topics []string, handler TransformHandler) error {
+	consumerHandler := &transactionalClaimHandler{
+		processor:  p,
+		handler:    handler,
+		handlerCtx: context.WithoutCancel(ctx),
+	}
+
+	for {
+		if err := p.consumerGroup.Consume(ctx, topics, consumerHandler); err != nil {
+			if stderrors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			p.logger.Error("error from transactional consumer", zap.Error(err))
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Close 처리기 종료 (파티션별 프로듀서는 할당이 끝날 때 닫힘)
+func (p *TransactionalProcessor) Close() error {
+	return p.consumerGroup.Close()
+}
+
+// process 메시지 하나를 트랜잭션으로 처리
+func (p *TransactionalProcessor) process(ctx context.Context, producer sarama.SyncProducer, handler TransformHandler, message *sarama.ConsumerMessage) error {
+	msg := &Message{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       message.Key,
+		Value:     message.Value,
+		Headers:   fromRecordHeaders(message.Headers),
+	}
+
+	if err := producer.BeginTxn(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	out := &txnPublisher{processor: p, producer: producer, causation: msg}
+	handlerErr := handler(ctx, msg, out)
+
+	if handlerErr != nil {
+		if !isPermanentFailure(handlerErr) {
+			p.abort(producer)
+			return handlerErr
+		}
+
+		// 재시도해도 결과가 같은 에러는 핸들러가 발행한 메시지를 버리고, 새 트랜잭션에서 DLQ 전송과 오프셋 커밋을 함께 처리
+		p.logger.Error("message sent to dead letter queue",
+			zap.String("topic", message.Topic),
+			zap.Int64("offset", message.Offset),
+			zap.Error(handlerErr))
+
+		if err := p.abort(producer); err != nil {
+			return err
+		}
+		if err := producer.BeginTxn(); err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		if _, _, err := producer.SendMessage(&sarama.ProducerMessage{
+			Topic: DLQTopic(message.Topic),
+			Key:   sarama.ByteEncoder(message.Key),
+			Value: sarama.ByteEncoder(message.Value),
+			Headers: withHeaders(message.Headers, map[string]string{
+				HeaderOriginalTopic:     message.Topic,
+				HeaderOriginalPartition: strconv.FormatInt(int64(message.Partition), 10),
+				HeaderOriginalOffset:    strconv.FormatInt(message.Offset, 10),
+				HeaderError:             handlerErr.Error(),
+			}),
+		}); err != nil {
+			p.abort(producer)
+			return fmt.Errorf("failed to send message to dead letter queue: %w", err)
+		}
+	}
+
+	if err := producer.AddMessageToTxn(message, p.groupID, nil); err != nil {
+		p.abort(producer)
+		return fmt.Errorf("failed to add offset to transaction: %w", err)
+	}
+
+	if err := producer.CommitTxn(); err != nil {
+		p.abort(producer)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// abort 진행 중인 트랜잭션 중단
+func (p *TransactionalProcessor) abort(producer sarama.SyncProducer) error {
+	if producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 &&
+		producer.TxnStatus()&sarama.ProducerTxnFlagAbortableError == 0 {
+		return nil
+	}
+	if err := producer.AbortTxn(); err != nil {
+		p.logger.Error("failed to abort transaction", zap.Error(err))
+		return fmt.Errorf("failed to abort transaction: %w", err)
+	}
+	return nil
+}
+
+// txnPublisher 진행 중인 트랜잭션에 메시지를 추가하는 Publisher
+type txnPublisher struct {
+	processor *TransactionalProcessor
+	producer  sarama.SyncProducer
+	causation *Message
+}
+
+// Publish 트랜잭션 내 이벤트 발행
+func (t *txnPublisher) Publish(ctx context.Context, topic string, key string, event interface{}) error {
+	return t.PublishWithHeaders(ctx, topic, key, eventHeaders(event), event)
+}
+
+// PublishWithHeaders 트랜잭션 내 헤더와 함께 이벤트 발행
+func (t *txnPublisher) PublishWithHeaders(ctx context.Context, topic string, key string, headers Headers, event interface{}) error {
+	p := t.processor
+
+	payload, contentType, err := encodePayload(p.codec, event)
+	if err != nil {
+		return err
+	}
+
+	headers = headers.clone()
+	headers[HeaderContentType] = contentType
+	if p.serviceName != "" && headers.Get(HeaderProducerService) == "" {
+		headers[HeaderProducerService] = p.serviceName
+	}
+	if headers.Get(HeaderCorrelationID) == "" {
+		headers[HeaderCorrelationID] = t.causation.CorrelationID()
+	}
+	if headers.Get(HeaderCausationID) == "" {
+		headers[HeaderCausationID] = t.causation.EventID()
+	}
+
+	if _, _, err := t.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(payload),
+		Headers: headers.toRecordHeaders(),
+	}); err != nil {
+		return fmt.Errorf("failed to send message in transaction: %w", err)
+	}
+	return nil
+}
+
+// Close 트랜잭션 Publisher 는 처리기가 관리하므로 아무 동작도 하지 않음
+func (t *txnPublisher) Close() error {
+	return nil
+}
+
+// transactionalClaimHandler 트랜잭션 처리기의 컨슈머 그룹 핸들러
+type transactionalClaimHandler struct {
+	processor  *TransactionalProcessor
+	handler    TransformHandler
+	handlerCtx context.Context
+}
+
+func (h *transactionalClaimHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *transactionalClaimHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *transactionalClaimHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	// 파티션을 넘겨받을 때 프로듀서를 초기화하여 이전 소유자를 펜싱
+	transactionalID := h.processor.transactionalID(claim.Topic(), claim.Partition())
+	producer, err := h.processor.newProducer(transactionalID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactional producer %s: %w", transactionalID, err)
+	}
+	defer func() {
+		if err := producer.Close(); err != nil {
+			h.processor.logger.Warn("failed to close transactional producer",
+				zap.String("transactionalId", transactionalID),
+				zap.Error(err))
+		}
+	}()
+
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if session.Context().Err() != nil {
+				return nil
+			}
+
+			if err := h.processor.process(h.handlerCtx, producer, h.handler, message); err != nil {
+				h.processor.logger.Error("transaction aborted, message will be redelivered",
+					zap.String("topic", message.Topic),
+					zap.Int64("offset", message.Offset),
+					zap.Error(err))
+
+				// 커밋되지 않은 오프셋부터 다시 읽도록 세션을 재시작
+				_ = waitUntil(session.Context(), time.Now().Add(h.processor.retryBackoff))
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package messaging
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"go.uber.org/zap"
+)
+
+func TestTransactionalProcessorCommitsOutputWithOffset(t *testing.T) {
+	producer := newRecordingProducer(t, "test-group-orders-2")
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic("payments"))
+	processor, ids := newTestProcessor(producer)
+
+	err := runClaim(processor, "orders", 2, func(ctx context.Context, msg *Message, out Publisher) error {
+		return out.Publish(ctx, "payments", string(msg.Key), map[string]string{"status": "ok"})
+	}, consumerMessage("orders", 2, 7))
+	if err != nil {
+		t.Fatalf("expected claim to finish without error, got %v", err)
+	}
+
+	if got := ids(); len(got) != 1 || got[0] != "test-group-orders-2" {
+		t.Fatalf("expected transactional id test-group-orders-2, got %v", got)
+	}
+	producer.assert(t, recorded{commits: 1, offsets: []int64{7}, closed: true})
+}
+
+func TestTransactionalProcessorAbortsOnTransientError(t *testing.T) {
+	producer := newRecordingProducer(t, "test-group-orders-0")
+	producer.ExpectSendMessageAndSucceed()
+	processor, _ := newTestProcessor(producer)
+
+	transient := stderrors.New("database unavailable")
+	err := runClaim(processor, "orders", 0, func(ctx context.Context, msg *Message, out Publisher) error {
+		if err := out.Publish(ctx, "payments", string(msg.Key), map[string]string{"status": "ok"}); err != nil {
+			return err
+		}
+		return transient
+	}, consumerMessage("orders", 0, 3), consumerMessage("orders", 0, 4))
+
+	// 발행한 메시지는 중단된 트랜잭션과 함께 버려지고, 오프셋을 넘기지 않은 채 세션을 재시작
+	if !stderrors.Is(err, transient) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	producer.assert(t, recorded{aborts: 1, closed: true})
+}
+
+func TestTransactionalProcessorSendsPermanentFailureToDLQ(t *testing.T) {
+	producer := newRecordingProducer(t, "test-group-orders-1")
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic("payments"))
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != DLQTopic("orders") {
+			return fmt.Errorf("expected topic %s, got %s", DLQTopic("orders"), msg.Topic)
+		}
+		headers := make(map[string]string)
+		for _, header := range msg.Headers {
+			headers[string(header.Key)] = string(header.Value)
+		}
+		if headers[HeaderOriginalTopic] != "orders" || headers[HeaderOriginalOffset] != "5" || headers[HeaderError] == "" {
+			return fmt.Errorf("unexpected dead letter headers: %v", headers)
+		}
+		return nil
+	})
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic("payments"))
+	processor, _ := newTestProcessor(producer)
+
+	err := runClaim(processor, "orders", 1, func(ctx context.Context, msg *Message, out Publisher) error {
+		if err := out.Publish(ctx, "payments", string(msg.Key), map[string]string{"status": "ok"}); err != nil {
+			return err
+		}
+		if msg.Offset == 5 {
+			return errors.New(errors.ErrCodeOutOfStock, "out of stock")
+		}
+		return nil
+	}, consumerMessage("orders", 1, 5), consumerMessage("orders", 1, 6))
+	if err != nil {
+		t.Fatalf("expected claim to finish without error, got %v", err)
+	}
+
+	// 핸들러 출력은 중단, DLQ 전송과 오프셋은 새 트랜잭션으로 커밋한 뒤 다음 메시지를 계속 처리
+	producer.assert(t, recorded{aborts: 1, commits: 2, offsets: []int64{5, 6}, closed: true})
+}
+
+// recorded 트랜잭션 프로듀서 호출 기록
+type recorded struct {
+	commits int
+	aborts  int
+	offsets []int64
+	closed  bool
+}
+
+// recordingProducer 커밋/중단 횟수와 트랜잭션에 추가된 오프셋을 기록하는 mock 프로듀서
+type recordingProducer struct {
+	*mocks.SyncProducer
+
+	mu  sync.Mutex
+	rec recorded
+}
+
+func newRecordingProducer(t *testing.T, transactionalID string) *recordingProducer {
+	return &recordingProducer{SyncProducer: mocks.NewSyncProducer(t, transactionalProducerConfig(transactionalID))}
+}
+
+func (p *recordingProducer) CommitTxn() error {
+	p.mu.Lock()
+	p.rec.commits++
+	p.mu.Unlock()
+	return p.SyncProducer.CommitTxn()
+}
+
+func (p *recordingProducer) AbortTxn() error {
+	p.mu.Lock()
+	p.rec.aborts++
+	p.mu.Unlock()
+	return p.SyncProducer.AbortTxn()
+}
+
+func (p *recordingProducer) AddMessageToTxn(msg *sarama.ConsumerMessage, groupID string, metadata *string) error {
+	if p.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 {
+		return stderrors.New("offset added outside of transaction")
+	}
+	p.mu.Lock()
+	p.rec.offsets = append(p.rec.offsets, msg.Offset)
+	p.mu.Unlock()
+	return p.SyncProducer.AddMessageToTxn(msg, groupID, metadata)
+}
+
+// Close 남은 기대 호출이 있으면 mock 이 테스트를 실패시킨다
+func (p *recordingProducer) Close() error {
+	p.mu.Lock()
+	p.rec.closed = true
+	p.mu.Unlock()
+	return p.SyncProducer.Close()
+}
+
+func (p *recordingProducer) assert(t *testing.T, want recorded) {
+	t.Helper()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rec.commits != want.commits || p.rec.aborts != want.aborts || p.rec.closed != want.closed ||
+		fmt.Sprint(p.rec.offsets) != fmt.Sprint(want.offsets) {
+		t.Fatalf("expected %+v, got %+v", want, p.rec)
+	}
+}
+
+// newTestProcessor mock 프로듀서를 사용하는 처리기 (요청된 transactional.id 를 기록)
+func newTestProcessor(producer sarama.SyncProducer) (*TransactionalProcessor, func() []string) {
+	var (
+		mu  sync.Mutex
+		ids []string
+	)
+	processor := &TransactionalProcessor{
+		groupID:     "test-group",
+		logger:      zap.NewNop(),
+		serviceName: "test-service",
+		codec:       JSONCodec{},
+		newProducer: func(transactionalID string) (sarama.SyncProducer, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			ids = append(ids, transactionalID)
+			return producer, nil
+		},
+	}
+	return processor, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), ids...)
+	}
+}
+
+// runClaim 메시지를 담은 파티션 할당 하나를 처리
+func runClaim(processor *TransactionalProcessor, topic string, partition int32, handler TransformHandler, messages ...*sarama.ConsumerMessage) error {
+	ch := make(chan *sarama.ConsumerMessage, len(messages))
+	for _, msg := range messages {
+		ch <- msg
+	}
+	close(ch)
+
+	claimHandler := &transactionalClaimHandler{
+		processor:  processor,
+		handler:    handler,
+		handlerCtx: context.Background(),
+	}
+	return claimHandler.ConsumeClaim(
+		&fakeSession{ctx: context.Background()},
+		&fakeClaim{topic: topic, partition: partition, messages: ch},
+	)
+}
+
+func consumerMessage(topic string, partition int32, offset int64) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		Key:       []byte("order-1"),
+		Value:     []byte(`{"orderId":1}`),
+	}
+}
+
+func expectTopic(topic string) mocks.MessageChecker {
+	return func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != topic {
+			return fmt.Errorf("expected topic %s, got %s", topic, msg.Topic)
+		}
+		return nil
+	}
+}
+
+// fakeSession 오프셋을 직접 커밋하지 않는 처리기를 위한 최소 컨슈머 그룹 세션
+type fakeSession struct {
+	ctx context.Context
+}
+
+func (s *fakeSession) Claims() map[string][]int32                  { return nil }
+func (s *fakeSession) MemberID() string                            { return "member-1" }
+func (s *fakeSession) GenerationID() int32                         { return 1 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)     {}
+func (s *fakeSession) Commit()                                     {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string)    {}
+func (s *fakeSession) MarkMessage(*sarama.ConsumerMessage, string) {}
+func (s *fakeSession) Context() context.Context                    { return s.ctx }
+
+// fakeClaim 미리 채운 채널을 돌려주는 파티션 할당
+type fakeClaim struct {
+	topic     string
+	partition int32
+	messages  chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return c.topic }
+func (c *fakeClaim) Partition() int32                         { return c.partition }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
@@ -0,0 +1,121 @@
+package outbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"go.uber.org/zap"
+)
+
+// AdminPath 관리 API 경로 (mux.Handle(AdminPath+"/", ...) 로 등록)
+const AdminPath = "/admin/outbox"
+
+// AdminHandler FAILED 이벤트 조회와 재큐잉 API
+//
+//	GET  /admin/outbox/failed?limit=50     FAILED 이벤트 목록
+//	POST /admin/outbox/failed/requeue      FAILED 이벤트 전체 재큐잉
+//	POST /admin/outbox/{id}/requeue        이벤트 하나 재큐잉
+//
+// 재큐잉하면 시도 횟수를 0으로 되돌리고 PENDING 으로 바꿔 다음 폴링에서 바로 발행한다.
+type AdminHandler struct {
+	repo   Repository
+	logger *zap.Logger
+}
+
+// NewAdminHandler Outbox 관리 API 생성
+func NewAdminHandler(repo Repository, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{repo: repo, logger: logger}
+}
+
+// ServeHTTP 경로와 메서드에 따라 요청 분기
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, AdminPath), "/")
+
+	switch {
+	case path == "failed":
+		if r.Method != http.MethodGet {
+			h.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.listFailed(w, r)
+	case path == "failed/requeue":
+		if r.Method != http.MethodPost {
+			h.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.requeueAll(w, r)
+	case strings.HasSuffix(path, "/requeue"):
+		if r.Method != http.MethodPost {
+			h.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(path, "/requeue"), 10, 64)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid outbox event ID")
+			return
+		}
+		h.requeue(w, r, id)
+	default:
+		h.respondError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *AdminHandler) listFailed(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 500 {
+			h.respondError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = n
+	}
+
+	failed, err := h.repo.FindFailed(r.Context(), limit)
+	if err != nil {
+		h.logger.Error("failed to list failed outbox events", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "failed to list failed outbox events")
+		return
+	}
+	h.respondJSON(w, http.StatusOK, failed)
+}
+
+func (h *AdminHandler) requeue(w http.ResponseWriter, r *http.Request, id int64) {
+	if err := h.repo.Requeue(r.Context(), id); err != nil {
+		if errors.IsCode(err, errors.ErrCodeNotFound) {
+			h.respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.logger.Error("failed to requeue outbox event", zap.Int64("id", id), zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "failed to requeue outbox event")
+		return
+	}
+
+	h.logger.Info("outbox event requeued", zap.Int64("id", id))
+	h.respondJSON(w, http.StatusOK, map[string]int64{"requeued": 1})
+}
+
+func (h *AdminHandler) requeueAll(w http.ResponseWriter, r *http.Request) {
+	count, err := h.repo.RequeueFailed(r.Context())
+	if err != nil {
+		h.logger.Error("failed to requeue failed outbox events", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "failed to requeue failed outbox events")
+		return
+	}
+
+	h.logger.Info("failed outbox events requeued", zap.Int64("count", count))
+	h.respondJSON(w, http.StatusOK, map[string]int64{"requeued": count})
+}
+
+func (h *AdminHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AdminHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
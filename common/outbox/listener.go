@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// NotifyChannel outbox_events 삽입 시 트리거가 NOTIFY 하는 채널
+const NotifyChannel = "outbox_events"
+
+// listenerPingInterval 알림이 없을 때 연결 상태를 확인하는 주기 (끊긴 연결을 빨리 발견해 재연결)
+const listenerPingInterval = 90 * time.Second
+
+// Listener outbox_events 삽입 알림을 LISTEN 해 발행 워커를 깨우는 리스너
+//
+// NOTIFY 는 트랜잭션 커밋 시점에 전달되므로 알림을 받으면 바로 점유할 수 있다.
+// 연결이 끊긴 사이의 알림은 유실되므로 재연결 직후에도 워커를 깨우고, 워커는 폴링을 보조 수단으로 유지한다.
+type Listener struct {
+	listener *pq.Listener
+	wakeup   chan struct{}
+	logger   *zap.Logger
+}
+
+// NewListener 리스너 생성 (dsn 은 별도 연결로 LISTEN 하며 끊기면 자동으로 재연결)
+func NewListener(dsn string, logger *zap.Logger) (*Listener, error) {
+	l := &Listener{
+		wakeup: make(chan struct{}, 1),
+		logger: logger,
+	}
+
+	l.listener = pq.NewListener(dsn, time.Second, time.Minute, l.handleEvent)
+	if err := l.listener.Listen(NotifyChannel); err != nil {
+		l.listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", NotifyChannel, err)
+	}
+	return l, nil
+}
+
+// Wakeups 새 이벤트가 커밋되면 신호를 보내는 채널 (신호는 하나로 합쳐지므로 받으면 비울 때까지 점유)
+func (l *Listener) Wakeups() <-chan struct{} {
+	return l.wakeup
+}
+
+// Start 알림을 워커 신호로 전달 (ctx 취소 시 연결을 닫고 종료)
+func (l *Listener) Start(ctx context.Context) {
+	defer l.listener.Close()
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.listener.Notify:
+			// 재연결 시에는 nil 이 전달되며, 끊긴 동안 놓친 이벤트가 있을 수 있으므로 이때도 깨운다
+			l.notify()
+		case <-ticker.C:
+			if err := l.listener.Ping(); err != nil {
+				l.logger.Warn("outbox listener ping failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (l *Listener) notify() {
+	select {
+	case l.wakeup <- struct{}{}:
+	default:
+		// 이미 대기 중인 신호가 있으면 워커가 어차피 점유하므로 버림
+	}
+}
+
+func (l *Listener) handleEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		l.logger.Warn("outbox listener disconnected", zap.Error(err))
+	case pq.ListenerEventReconnected:
+		l.logger.Info("outbox listener reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.logger.Warn("outbox listener connection attempt failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/retry"
+)
+
+// Outbox 이벤트 상태
+const (
+	StatusPending = "PENDING"
+	StatusSent    = "SENT"
+	// StatusFailed 최대 시도 횟수를 넘겨 더 이상 발행하지 않는 상태 (관리 API로 재큐잉)
+	StatusFailed = "FAILED"
+)
+
+// DefaultRetryConfig 발행 실패 시 재시도 설정
+//
+// 시도할 때마다 대기 시간이 1초부터 2배씩 늘어나 최대 5분까지 커지고, 10번째 실패에서 FAILED 로 전환된다
+// (약 8분 동안의 브로커 장애를 견딘다). MaxElapsedTime 은 사용하지 않는다.
+func DefaultRetryConfig() retry.Config {
+	return retry.Config{
+		MaxAttempts:        10,
+		InitialInterval:    time.Second,
+		MaxInterval:        5 * time.Minute,
+		BackoffCoefficient: 2.0,
+	}
+}
@@ -0,0 +1,213 @@
+package outbox
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LSN PostgreSQL WAL 위치 (텍스트 표현 "16/B374D848")
+type LSN uint64
+
+// ParseLSN 텍스트 표현의 LSN 파싱
+func ParseLSN(s string) (LSN, error) {
+	hi, lo, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid LSN %q", s)
+	}
+	upper, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q: %w", s, err)
+	}
+	lower, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q: %w", s, err)
+	}
+	return LSN(upper<<32 | lower), nil
+}
+
+// String pg_lsn 텍스트 표현
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint64(l)&0xFFFFFFFF)
+}
+
+// pgoutput 논리 복제 메시지 종류 (프로토콜 버전 1 중 릴레이가 사용하는 것만)
+const (
+	pgoutputBegin    = 'B'
+	pgoutputCommit   = 'C'
+	pgoutputRelation = 'R'
+	pgoutputInsert   = 'I'
+	pgoutputUpdate   = 'U'
+)
+
+// tuple 컬럼 값 종류
+const (
+	tupleNull           = 'n'
+	tupleUnchangedToast = 'u'
+	tupleText           = 't'
+)
+
+// relation Relation 메시지로 전달되는 테이블 정보
+type relation struct {
+	namespace string
+	name      string
+	columns   []string
+}
+
+// tupleValue 텍스트 형식 컬럼 값 (unchanged 는 TOAST 값이 바뀌지 않아 생략된 경우)
+type tupleValue struct {
+	value     string
+	null      bool
+	unchanged bool
+}
+
+// pgoutputMessage 디코딩한 메시지
+type pgoutputMessage struct {
+	kind byte
+	// Begin/Commit
+	xid    uint32
+	endLSN LSN
+	// Relation/Insert/Update
+	relationID uint32
+	relation   *relation
+	tuple      []tupleValue
+}
+
+// decodePgoutput pgoutput 메시지 하나 디코딩 (사용하지 않는 종류는 kind 만 채워 반환)
+func decodePgoutput(data []byte) (*pgoutputMessage, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty pgoutput message")
+	}
+
+	r := &pgoutputReader{buf: data[1:]}
+	msg := &pgoutputMessage{kind: data[0]}
+
+	switch msg.kind {
+	case pgoutputBegin:
+		r.uint64() // 커밋 LSN
+		r.uint64() // 커밋 시각
+		msg.xid = r.uint32()
+	case pgoutputCommit:
+		r.byte()   // flags
+		r.uint64() // 커밋 LSN
+		msg.endLSN = LSN(r.uint64())
+		r.uint64() // 커밋 시각
+	case pgoutputRelation:
+		msg.relationID = r.uint32()
+		rel := &relation{namespace: r.string(), name: r.string()}
+		r.byte() // replica identity
+		n := int(r.uint16())
+		for i := 0; i < n; i++ {
+			r.byte() // flags
+			rel.columns = append(rel.columns, r.string())
+			r.uint32() // 타입 OID
+			r.uint32() // typmod
+		}
+		msg.relation = rel
+	case pgoutputInsert:
+		msg.relationID = r.uint32()
+		if kind := r.byte(); kind != 'N' {
+			return nil, fmt.Errorf("unexpected insert tuple kind %q", kind)
+		}
+		msg.tuple = r.tuple()
+	case pgoutputUpdate:
+		msg.relationID = r.uint32()
+		kind := r.byte()
+		if kind == 'K' || kind == 'O' {
+			// 이전 키/행은 건너뛰고 새 행만 사용
+			r.tuple()
+			kind = r.byte()
+		}
+		if kind != 'N' {
+			return nil, fmt.Errorf("unexpected update tuple kind %q", kind)
+		}
+		msg.tuple = r.tuple()
+	}
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode pgoutput message %q: %w", msg.kind, r.err)
+	}
+	return msg, nil
+}
+
+// pgoutputReader 빅엔디언 바이너리 리더 (한 번 실패하면 이후 읽기는 0 값 반환)
+type pgoutputReader struct {
+	buf []byte
+	err error
+}
+
+func (r *pgoutputReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.buf) < n {
+		r.err = fmt.Errorf("message truncated")
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *pgoutputReader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *pgoutputReader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *pgoutputReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *pgoutputReader) uint64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *pgoutputReader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(r.buf, 0)
+	if i < 0 {
+		r.err = fmt.Errorf("unterminated string")
+		return ""
+	}
+	s := string(r.buf[:i])
+	r.buf = r.buf[i+1:]
+	return s
+}
+
+func (r *pgoutputReader) tuple() []tupleValue {
+	n := int(r.uint16())
+	values := make([]tupleValue, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		switch kind := r.byte(); kind {
+		case tupleNull:
+			values = append(values, tupleValue{null: true})
+		case tupleUnchangedToast:
+			values = append(values, tupleValue{unchanged: true})
+		case tupleText:
+			size := int(r.uint32())
+			values = append(values, tupleValue{value: string(r.next(size))})
+		default:
+			r.err = fmt.Errorf("unexpected tuple value kind %q", kind)
+		}
+	}
+	return values
+}
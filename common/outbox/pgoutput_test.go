@@ -0,0 +1,211 @@
+package outbox
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseLSN(t *testing.T) {
+	tests := []struct {
+		text string
+		want LSN
+	}{
+		{"0/0", 0},
+		{"0/16B3748", 0x16B3748},
+		{"16/B374D848", 0x16B374D848},
+		{"FFFFFFFF/FFFFFFFF", LSN(^uint64(0))},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLSN(tt.text)
+		if err != nil {
+			t.Fatalf("ParseLSN(%q) failed: %v", tt.text, err)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseLSN(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+		if got.String() != tt.text {
+			t.Fatalf("LSN(%d).String() = %q, want %q", got, got.String(), tt.text)
+		}
+	}
+}
+
+func TestParseLSNInvalid(t *testing.T) {
+	for _, text := range []string{"", "16B374D848", "G/0", "0/G", "100000000/0", "0/100000000"} {
+		if _, err := ParseLSN(text); err == nil {
+			t.Fatalf("expected ParseLSN(%q) to fail", text)
+		}
+	}
+}
+
+func TestLSNOrdering(t *testing.T) {
+	low, _ := ParseLSN("1/FFFFFFFF")
+	high, _ := ParseLSN("2/0")
+	if low >= high {
+		t.Fatalf("expected %s < %s", low, high)
+	}
+}
+
+func TestDecodePgoutputTransaction(t *testing.T) {
+	begin := mustDecode(t, frame('B').u64(0x1000).u64(0).u32(742))
+	if begin.kind != pgoutputBegin || begin.xid != 742 {
+		t.Fatalf("unexpected begin message %+v", begin)
+	}
+
+	commit := mustDecode(t, frame('C').byte(0).u64(0x1000).u64(0x16B374D848).u64(0))
+	if commit.kind != pgoutputCommit || commit.endLSN.String() != "16/B374D848" {
+		t.Fatalf("unexpected commit message %+v", commit)
+	}
+}
+
+func TestDecodePgoutputRelation(t *testing.T) {
+	msg := mustDecode(t, frame('R').u32(16385).str("public").str("outbox_events").byte('d').
+		u16(3).
+		byte(1).str("id").u32(20).u32(0xFFFFFFFF).
+		byte(0).str("status").u32(1043).u32(0xFFFFFFFF).
+		byte(0).str("payload").u32(3802).u32(0xFFFFFFFF))
+
+	if msg.kind != pgoutputRelation || msg.relationID != 16385 {
+		t.Fatalf("unexpected relation message %+v", msg)
+	}
+	if msg.relation.namespace != "public" || msg.relation.name != "outbox_events" {
+		t.Fatalf("unexpected relation %+v", msg.relation)
+	}
+	if len(msg.relation.columns) != 3 || msg.relation.columns[0] != "id" || msg.relation.columns[2] != "payload" {
+		t.Fatalf("unexpected columns %v", msg.relation.columns)
+	}
+}
+
+func TestDecodePgoutputInsert(t *testing.T) {
+	msg := mustDecode(t, frame('I').u32(16385).byte('N').
+		u16(3).text("42").text("PENDING").null())
+
+	if msg.kind != pgoutputInsert || msg.relationID != 16385 {
+		t.Fatalf("unexpected insert message %+v", msg)
+	}
+	want := []tupleValue{{value: "42"}, {value: "PENDING"}, {null: true}}
+	assertTuple(t, msg.tuple, want)
+}
+
+func TestDecodePgoutputUpdate(t *testing.T) {
+	// 새 행만 있는 경우 (unchanged TOAST 컬럼 포함)
+	msg := mustDecode(t, frame('U').u32(16385).byte('N').
+		u16(3).text("42").text("PENDING").unchanged())
+	if msg.kind != pgoutputUpdate {
+		t.Fatalf("unexpected update message %+v", msg)
+	}
+	assertTuple(t, msg.tuple, []tupleValue{{value: "42"}, {value: "PENDING"}, {unchanged: true}})
+
+	// REPLICA IDENTITY FULL 등으로 이전 행이 함께 오는 경우 새 행만 사용
+	msg = mustDecode(t, frame('U').u32(16385).
+		byte('O').u16(3).text("42").text("FAILED").null().
+		byte('N').u16(3).text("42").text("PENDING").unchanged())
+	assertTuple(t, msg.tuple, []tupleValue{{value: "42"}, {value: "PENDING"}, {unchanged: true}})
+}
+
+func TestDecodePgoutputIgnoredKind(t *testing.T) {
+	// 릴레이가 사용하지 않는 메시지(Truncate 등)는 kind 만 채워 반환
+	msg := mustDecode(t, frame('T').u32(1))
+	if msg.kind != 'T' || msg.tuple != nil || msg.relation != nil {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+}
+
+func TestDecodePgoutputMalformed(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":              nil,
+		"truncated begin":    frame('B').u64(0x1000).bytes(),
+		"unterminated name":  append(frame('R').u32(1).bytes(), "public"...),
+		"insert without new": frame('I').u32(1).byte('K').u16(0).bytes(),
+		"unknown value kind": frame('I').u32(1).byte('N').u16(1).byte('x').bytes(),
+		"truncated value":    frame('I').u32(1).byte('N').u16(1).byte('t').u32(10).raw("abc").bytes(),
+	}
+
+	for name, data := range tests {
+		if _, err := decodePgoutput(data); err == nil {
+			t.Fatalf("%s: expected decode error", name)
+		}
+	}
+}
+
+// frameBuilder pgoutput 메시지 바이트 생성기
+type frameBuilder struct {
+	buf bytes.Buffer
+}
+
+func frame(kind byte) *frameBuilder {
+	f := &frameBuilder{}
+	f.buf.WriteByte(kind)
+	return f
+}
+
+func (f *frameBuilder) byte(b byte) *frameBuilder {
+	f.buf.WriteByte(b)
+	return f
+}
+
+func (f *frameBuilder) u16(v uint16) *frameBuilder {
+	f.buf.Write(binary.BigEndian.AppendUint16(nil, v))
+	return f
+}
+
+func (f *frameBuilder) u32(v uint32) *frameBuilder {
+	f.buf.Write(binary.BigEndian.AppendUint32(nil, v))
+	return f
+}
+
+func (f *frameBuilder) u64(v uint64) *frameBuilder {
+	f.buf.Write(binary.BigEndian.AppendUint64(nil, v))
+	return f
+}
+
+func (f *frameBuilder) str(s string) *frameBuilder {
+	f.buf.WriteString(s)
+	f.buf.WriteByte(0)
+	return f
+}
+
+func (f *frameBuilder) raw(s string) *frameBuilder {
+	f.buf.WriteString(s)
+	return f
+}
+
+func (f *frameBuilder) text(s string) *frameBuilder {
+	return f.byte(tupleText).u32(uint32(len(s))).raw(s)
+}
+
+func (f *frameBuilder) null() *frameBuilder {
+	return f.byte(tupleNull)
+}
+
+func (f *frameBuilder) unchanged() *frameBuilder {
+	return f.byte(tupleUnchangedToast)
+}
+
+func (f *frameBuilder) bytes() []byte {
+	return f.buf.Bytes()
+}
+
+func mustDecode(t *testing.T, f *frameBuilder) *pgoutputMessage {
+	t.Helper()
+
+	msg, err := decodePgoutput(f.bytes())
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	return msg
+}
+
+func assertTuple(t *testing.T, got, want []tupleValue) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d values, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("value %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"github.com/kyungseok/msa-saga-go-examples/common/retry"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// 발행 방식 (서비스 설정 OUTBOX_RELAY)
+const (
+	// RelayModePolling outbox_events 를 점유 쿼리로 폴링하는 Worker
+	RelayModePolling = "polling"
+	// RelayModeCDC 논리 복제(pgoutput)로 커밋된 변경을 읽는 Relay
+	RelayModeCDC = "cdc"
+)
+
+// DefaultPublication outbox_events 의 INSERT/UPDATE 를 내보내는 publication (init SQL 에서 생성)
+const DefaultPublication = "outbox_events_pub"
+
+// pgErrObjectInUse 다른 세션이 복제 슬롯을 사용 중일 때의 에러 코드
+const pgErrObjectInUse = "55006"
+
+// RelayConfig CDC 릴레이 설정
+type RelayConfig struct {
+	// SlotName 논리 복제 슬롯 이름 (서비스마다 하나, 없으면 시작 시 생성)
+	SlotName string
+	// Publication 구독할 publication 이름
+	Publication string
+	// Interval 알림이 없을 때 변경을 확인하는 보조 주기
+	Interval time.Duration
+	// MaxChanges 한 번에 읽을 최대 변경 수 (트랜잭션 중간에서는 끊지 않으므로 더 많이 읽을 수 있음)
+	MaxChanges int
+	// Retry 발행 실패 시 백오프 (커밋 순서를 지키기 위해 성공할 때까지 같은 트랜잭션부터 재시도)
+	Retry retry.Config
+}
+
+// DefaultRelayConfig 기본 릴레이 설정 (5초 보조 주기, 1000건씩 읽기)
+func DefaultRelayConfig(slotName string) RelayConfig {
+	return RelayConfig{
+		SlotName:    slotName,
+		Publication: DefaultPublication,
+		Interval:    5 * time.Second,
+		MaxChanges:  1000,
+		Retry:       DefaultRetryConfig(),
+	}
+}
+
+// Relay WAL 의 outbox_events 변경을 커밋 순서대로 발행하는 CDC 릴레이
+//
+// 테이블을 폴링하지 않고 복제 슬롯에서 커밋된 트랜잭션을 읽어, 트랜잭션 안의 이벤트를 삽입 순서대로 발행한다.
+// 트랜잭션을 모두 발행하면 끝 LSN 을 outbox_relay_checkpoints 에 기록하고 슬롯을 전진시킨다.
+// 슬롯 전진은 DB 가 죽으면 다음 체크포인트 이전 위치로 되돌아갈 수 있으므로, 저장한 체크포인트 이하의
+// 트랜잭션은 다시 읽더라도 발행하지 않는다.
+//
+// pg_logical_slot_peek_binary_changes 는 호출하는 동안만 슬롯을 점유하므로 슬롯만으로는 인스턴스 간 배타가 보장되지 않는다.
+// 읽기부터 발행, 체크포인트 기록까지를 슬롯 이름의 advisory lock(pg_try_advisory_xact_lock) 안에서 수행하고,
+// 잠금을 얻은 뒤 체크포인트를 다시 읽어 다른 인스턴스가 이미 발행한 트랜잭션은 건너뛴다.
+// 여러 인스턴스를 띄우면 잠금을 얻은 하나만 발행하고 나머지는 다음 주기에 다시 시도한다.
+// 관리 API로 재큐잉한 이벤트(PENDING 으로 바뀐 UPDATE)도 다시 발행한다.
+type Relay struct {
+	db        *sql.DB
+	repo      Repository
+	publisher messaging.Publisher
+	config    RelayConfig
+	logger    *zap.Logger
+	wakeup    <-chan struct{}
+
+	relations  map[uint32]*relation
+	checkpoint LSN
+	ready      bool
+}
+
+// RelayOption CDC 릴레이 옵션
+type RelayOption func(*Relay)
+
+// WithRelayWakeup 신호를 받으면 보조 주기를 기다리지 않고 바로 변경을 읽음 (예: Listener.Wakeups())
+func WithRelayWakeup(wakeup <-chan struct{}) RelayOption {
+	return func(r *Relay) {
+		r.wakeup = wakeup
+	}
+}
+
+// NewRelay CDC 릴레이 생성 (DB 는 wal_level=logical 이어야 하고 사용자에게 REPLICATION 권한이 필요)
+func NewRelay(db *sql.DB, repo Repository, publisher messaging.Publisher, config RelayConfig, logger *zap.Logger, opts ...RelayOption) *Relay {
+	r := &Relay{
+		db:        db,
+		repo:      repo,
+		publisher: publisher,
+		config:    config,
+		logger:    logger,
+		relations: make(map[uint32]*relation),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start 릴레이 시작 (ctx 취소 시 종료)
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	r.logger.Info("outbox relay started",
+		zap.String("slot", r.config.SlotName),
+		zap.String("publication", r.config.Publication))
+
+	failures := 0
+	for {
+		if err := r.drain(ctx); err != nil && ctx.Err() == nil {
+			failures++
+			delay := r.config.Retry.Backoff(failures)
+			r.logger.Error("failed to relay outbox events",
+				zap.Int("failures", failures),
+				zap.Duration("retryIn", delay),
+				zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				r.logger.Info("outbox relay stopped")
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+		failures = 0
+
+		// wakeup 이 없으면 nil 채널이므로 보조 주기만 동작
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped")
+			return
+		case <-r.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain 읽을 변경이 없을 때까지 반복 발행
+func (r *Relay) drain(ctx context.Context) error {
+	if !r.ready {
+		if err := r.setup(ctx); err != nil {
+			return err
+		}
+		r.ready = true
+	}
+
+	for ctx.Err() == nil {
+		n, err := r.relayOnce(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// setup 복제 슬롯이 없으면 생성
+func (r *Relay) setup(ctx context.Context) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1)`,
+		r.config.SlotName,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to look up replication slot: %w", err)
+	}
+
+	if !exists {
+		if _, err := r.db.ExecContext(ctx,
+			`SELECT pg_create_logical_replication_slot($1, 'pgoutput')`,
+			r.config.SlotName,
+		); err != nil {
+			return fmt.Errorf("failed to create replication slot %s: %w", r.config.SlotName, err)
+		}
+		r.logger.Info("replication slot created", zap.String("slot", r.config.SlotName))
+
+		// 슬롯 생성 전에 커밋된 PENDING 이벤트는 WAL 로 읽을 수 없으므로 갱신해 변경 스트림에 다시 흘려보냄
+		result, err := r.db.ExecContext(ctx,
+			`UPDATE outbox_events SET next_attempt_at = NOW() WHERE status = $1`,
+			StatusPending,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to replay pending events: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			r.logger.Info("pending outbox events replayed through relay", zap.Int64("count", rows))
+		}
+	}
+
+	return nil
+}
+
+// loadCheckpoint 저장된 체크포인트 조회 (없으면 0)
+func (r *Relay) loadCheckpoint(ctx context.Context, tx *sql.Tx) (LSN, error) {
+	var checkpoint string
+	err := tx.QueryRowContext(ctx,
+		`SELECT lsn::text FROM outbox_relay_checkpoints WHERE slot_name = $1`,
+		r.config.SlotName,
+	).Scan(&checkpoint)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load relay checkpoint: %w", err)
+	}
+	return ParseLSN(checkpoint)
+}
+
+// relayTxn 커밋된 트랜잭션 하나에서 발행할 이벤트
+type relayTxn struct {
+	xid    uint32
+	endLSN LSN
+	events []*Event
+	// requeued 재큐잉되어 다시 발행할 이벤트 ID (UPDATE 는 TOAST 값이 생략될 수 있어 다시 조회)
+	requeued []int64
+}
+
+// relayOnce 슬롯에서 변경을 한 번 읽어 발행하고 발행한 트랜잭션 수 반환
+//
+// 잠금용 트랜잭션은 advisory lock 과 체크포인트 조회에만 쓰고, 슬롯 읽기와 발행, 기록은 별도 연결에서
+// 자동 커밋으로 수행한다. 잠금은 relayOnce 가 끝나 트랜잭션을 롤백할 때 풀린다.
+func (r *Relay) relayOnce(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin relay lock transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT pg_try_advisory_xact_lock(hashtext($1))`,
+		r.config.SlotName,
+	).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to acquire relay lock: %w", err)
+	}
+	if !locked {
+		// 다른 인스턴스가 발행 중
+		r.logger.Debug("relay lock held by another instance", zap.String("slot", r.config.SlotName))
+		return 0, nil
+	}
+
+	// 다른 인스턴스가 잠금을 갖고 있던 동안 전진한 위치부터 이어서 발행
+	checkpoint, err := r.loadCheckpoint(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint > r.checkpoint {
+		r.checkpoint = checkpoint
+	}
+
+	// 변경이 없을 때 슬롯을 전진시킬 위치 (읽기 전에 구해야 그 사이 커밋된 트랜잭션을 건너뛰지 않음)
+	var current string
+	if err := r.db.QueryRowContext(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&current); err != nil {
+		return 0, fmt.Errorf("failed to get current WAL position: %w", err)
+	}
+
+	txns, err := r.peek(ctx)
+	if err != nil {
+		var pqErr *pq.Error
+		if stderrors.As(err, &pqErr) && pqErr.Code == pgErrObjectInUse {
+			// 슬롯을 직접 읽는 다른 세션이 있음
+			r.logger.Debug("replication slot in use by another session", zap.String("slot", r.config.SlotName))
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if len(txns) == 0 {
+		// 관련 없는 WAL 이 슬롯에 쌓이지 않도록 현재 위치까지 전진
+		lsn, err := ParseLSN(current)
+		if err != nil {
+			return 0, err
+		}
+		return 0, r.advance(ctx, lsn)
+	}
+
+	// 발행을 마친 트랜잭션까지만 전진하고, 실패한 트랜잭션부터 다음에 다시 읽음
+	var advanced LSN
+	var relayErr error
+	processed := 0
+	for _, txn := range txns {
+		if txn.endLSN > r.checkpoint {
+			if relayErr = r.publishTxn(ctx, txn); relayErr != nil {
+				break
+			}
+			if relayErr = r.saveCheckpoint(ctx, txn.endLSN); relayErr != nil {
+				break
+			}
+		}
+		advanced = txn.endLSN
+		processed++
+	}
+
+	if advanced > 0 {
+		if err := r.advance(context.WithoutCancel(ctx), advanced); err != nil && relayErr == nil {
+			relayErr = err
+		}
+	}
+	return processed, relayErr
+}
+
+// peek 슬롯을 전진시키지 않고 커밋된 트랜잭션을 읽음 (발행에 성공한 뒤에만 전진)
+func (r *Relay) peek(ctx context.Context) ([]*relayTxn, error) {
+	query := `
+		SELECT data
+		FROM pg_logical_slot_peek_binary_changes($1, NULL, $2, 'proto_version', '1', 'publication_names', $3)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, r.config.SlotName, r.config.MaxChanges, r.config.Publication)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replication slot: %w", err)
+	}
+	defer rows.Close()
+
+	var txns []*relayTxn
+	var txn *relayTxn
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan change: %w", err)
+		}
+
+		msg, err := decodePgoutput(data)
+		if err != nil {
+			return nil, err
+		}
+
+		switch msg.kind {
+		case pgoutputBegin:
+			txn = &relayTxn{xid: msg.xid}
+		case pgoutputCommit:
+			if txn != nil {
+				txn.endLSN = msg.endLSN
+				txns = append(txns, txn)
+			}
+			txn = nil
+		case pgoutputRelation:
+			r.relations[msg.relationID] = msg.relation
+		case pgoutputInsert, pgoutputUpdate:
+			if txn == nil {
+				continue
+			}
+			if err := r.collect(txn, msg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read replication slot: %w", err)
+	}
+
+	return txns, nil
+}
+
+// collect 변경 행을 트랜잭션의 발행 대상에 추가 (PENDING 상태인 행만)
+func (r *Relay) collect(txn *relayTxn, msg *pgoutputMessage) error {
+	rel, ok := r.relations[msg.relationID]
+	if !ok {
+		return fmt.Errorf("unknown relation %d", msg.relationID)
+	}
+	if rel.name != "outbox_events" {
+		return nil
+	}
+
+	values := make(map[string]tupleValue, len(rel.columns))
+	for i, column := range rel.columns {
+		if i < len(msg.tuple) {
+			values[column] = msg.tuple[i]
+		}
+	}
+
+	if values["status"].value != StatusPending {
+		// 전송 완료/실패 표시 같은 상태 변경은 발행 대상이 아님
+		return nil
+	}
+
+	id, err := strconv.ParseInt(values["id"].value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid outbox event id %q: %w", values["id"].value, err)
+	}
+
+	if msg.kind == pgoutputUpdate {
+		txn.requeued = append(txn.requeued, id)
+		return nil
+	}
+
+	aggregateID, err := strconv.ParseInt(values["aggregate_id"].value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid aggregate id %q: %w", values["aggregate_id"].value, err)
+	}
+	attempts, _ := strconv.Atoi(values["attempts"].value)
+
+	txn.events = append(txn.events, &Event{
+		ID:            id,
+		AggregateType: values["aggregate_type"].value,
+		AggregateID:   aggregateID,
+		EventType:     values["event_type"].value,
+		PartitionKey:  values["partition_key"].value,
+		Payload:       json.RawMessage(values["payload"].value),
+		Status:        StatusPending,
+		Attempts:      attempts,
+	})
+	return nil
+}
+
+// publishTxn 트랜잭션의 이벤트를 삽입 순서대로 발행하고 전송 완료 표시
+func (r *Relay) publishTxn(ctx context.Context, txn *relayTxn) error {
+	pending := txn.events
+	sort.Slice(txn.requeued, func(i, j int) bool { return txn.requeued[i] < txn.requeued[j] })
+	for _, id := range txn.requeued {
+		event, err := r.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if event.Status == StatusPending {
+			pending = append(pending, event)
+		}
+	}
+
+	for _, event := range pending {
+		key, headers, err := prepareEvent(event)
+		if err != nil {
+			// 페이로드가 깨진 이벤트는 재시도해도 발행할 수 없으므로 FAILED 로 두고 다음 이벤트 진행
+			r.logger.Error("outbox event failed permanently",
+				zap.Int64("eventId", event.ID),
+				zap.String("eventType", event.EventType),
+				zap.Error(err))
+			if err := r.repo.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.publisher.PublishWithHeaders(ctx, event.EventType, key, headers, encodedPayload(event)); err != nil {
+			return fmt.Errorf("failed to publish outbox event %d: %w", event.ID, err)
+		}
+
+		if err := r.repo.MarkSent(ctx, event.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(pending) > 0 {
+		r.logger.Debug("outbox transaction relayed",
+			zap.Uint32("xid", txn.xid),
+			zap.Stringer("lsn", txn.endLSN),
+			zap.Int("count", len(pending)))
+	}
+	return nil
+}
+
+// saveCheckpoint 발행을 마친 트랜잭션의 끝 LSN 기록 (저장된 위치보다 앞설 때만 갱신하여 되돌아가지 않음)
+func (r *Relay) saveCheckpoint(ctx context.Context, lsn LSN) error {
+	query := `
+		INSERT INTO outbox_relay_checkpoints (slot_name, lsn, updated_at)
+		VALUES ($1, $2::pg_lsn, NOW())
+		ON CONFLICT (slot_name) DO UPDATE SET lsn = EXCLUDED.lsn, updated_at = EXCLUDED.updated_at
+		WHERE outbox_relay_checkpoints.lsn < EXCLUDED.lsn
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, r.config.SlotName, lsn.String()); err != nil {
+		return fmt.Errorf("failed to save relay checkpoint: %w", err)
+	}
+	r.checkpoint = lsn
+	return nil
+}
+
+// advance 슬롯의 확인 위치를 lsn 까지 전진 (이미 지난 위치면 무시)
+func (r *Relay) advance(ctx context.Context, lsn LSN) error {
+	query := `
+		SELECT pg_replication_slot_advance($1, $2::pg_lsn)
+		FROM pg_replication_slots
+		WHERE slot_name = $1 AND confirmed_flush_lsn < $2::pg_lsn
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, r.config.SlotName, lsn.String()); err != nil {
+		var pqErr *pq.Error
+		if stderrors.As(err, &pqErr) && pqErr.Code == pgErrObjectInUse {
+			return nil
+		}
+		return fmt.Errorf("failed to advance replication slot: %w", err)
+	}
+	return nil
+}
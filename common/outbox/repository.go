@@ -0,0 +1,415 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/lib/pq"
+)
+
+// Aggregate 이벤트를 발생시킨 집합체 (예: order/123, payment/45)
+type Aggregate struct {
+	Type string
+	ID   int64
+}
+
+// Event Outbox 이벤트 (outbox_events 행)
+type Event struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   int64           `json:"aggregateId"`
+	EventType     string          `json:"eventType"`
+	PartitionKey  string          `json:"partitionKey"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"lastError,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// Repository Outbox 저장소 인터페이스
+type Repository interface {
+	// Enqueue 비즈니스 트랜잭션 안에서 이벤트 저장 (트랜잭션과 함께 커밋되어야 발행됨)
+	//
+	// 파티션 키는 이벤트의 주문 ID(없으면 aggregate ID)로 저장되어, 같은 SAGA의 이벤트가
+	// 같은 파티션에 커밋 순서대로 발행된다.
+	Enqueue(ctx context.Context, tx *sql.Tx, aggregate Aggregate, event events.Event) error
+	// Claim 발행할 이벤트를 lease 동안 점유하고 생성 순서로 반환
+	// (같은 파티션 키의 앞선 이벤트가 아직 PENDING 이면 그 뒤 이벤트는 점유하지 않음)
+	Claim(ctx context.Context, workerID string, limit int, lease time.Duration) ([]*Event, error)
+	// MarkSent 전송 완료 표시
+	MarkSent(ctx context.Context, id int64) error
+	// MarkSentBatch 여러 이벤트를 한 번에 전송 완료 표시
+	MarkSentBatch(ctx context.Context, ids []int64) error
+	// MarkRetry 발행 실패 기록 후 delay 뒤에 다시 시도하도록 점유 해제
+	MarkRetry(ctx context.Context, id int64, lastErr string, delay time.Duration) error
+	// MarkFailed 최대 시도 횟수를 넘긴 이벤트를 FAILED 로 표시
+	MarkFailed(ctx context.Context, id int64, lastErr string) error
+	// Get ID로 이벤트 조회 (없으면 ErrCodeNotFound)
+	Get(ctx context.Context, id int64) (*Event, error)
+	// FindFailed FAILED 이벤트 목록 (최신순)
+	FindFailed(ctx context.Context, limit int) ([]*Event, error)
+	// Requeue FAILED 이벤트를 PENDING 으로 되돌림 (없으면 ErrCodeNotFound)
+	Requeue(ctx context.Context, id int64) error
+	// RequeueFailed 모든 FAILED 이벤트를 PENDING 으로 되돌리고 개수 반환
+	RequeueFailed(ctx context.Context) (int64, error)
+	// PurgeSent 전송 후 olderThan 이 지난 SENT 이벤트를 최대 limit 건 삭제하고 개수 반환
+	PurgeSent(ctx context.Context, olderThan time.Duration, limit int) (int64, error)
+	// ArchiveSent 전송 후 olderThan 이 지난 SENT 이벤트를 최대 limit 건 아카이브 테이블로 옮기고 개수 반환
+	ArchiveSent(ctx context.Context, olderThan time.Duration, limit int) (int64, error)
+	// EnsureArchivePartition month 가 속한 달의 아카이브 파티션 생성 (이미 있으면 무시)
+	EnsureArchivePartition(ctx context.Context, month time.Time) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository PostgreSQL outbox_events 테이블 기반 저장소 생성
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// Enqueue 비즈니스 트랜잭션 안에서 이벤트 저장
+func (r *repository) Enqueue(ctx context.Context, tx *sql.Tx, aggregate Aggregate, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeSerializationError, "failed to marshal event", err)
+	}
+
+	query := `
+		INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, partition_key, payload, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		aggregate.Type,
+		aggregate.ID,
+		string(event.GetEventType()),
+		PartitionKey(aggregate, event),
+		payload,
+		StatusPending,
+	)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to insert outbox event", err)
+	}
+
+	return nil
+}
+
+// PartitionKey 이벤트의 파티션 키 (주문 ID, 주문과 무관한 이벤트면 aggregate ID)
+func PartitionKey(aggregate Aggregate, event events.Event) string {
+	if orderID, ok := events.OrderIDOf(event); ok {
+		return strconv.FormatInt(orderID, 10)
+	}
+	return strconv.FormatInt(aggregate.ID, 10)
+}
+
+// Claim 발행할 이벤트 점유
+//
+// FOR UPDATE SKIP LOCKED 로 다른 워커가 점유 중인 행은 건너뛰고, lease 동안 claimed_until 을 설정해
+// 다른 인스턴스가 같은 이벤트를 가져가지 못하게 한다. 워커가 죽어 lease 가 만료된 행은 다시 점유된다.
+//
+// 같은 파티션 키의 이벤트는 키마다 가장 앞선 PENDING 이벤트 하나만 점유하므로, 여러 워커가 병렬로
+// 발행하거나 앞선 이벤트가 재시도 대기 중이어도 뒤 이벤트가 먼저 발행되지 않는다.
+func (r *repository) Claim(ctx context.Context, workerID string, limit int, lease time.Duration) ([]*Event, error) {
+	query := `
+		UPDATE outbox_events
+		SET claimed_by = $1, claimed_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM outbox_events o
+			WHERE status = $4
+			  AND next_attempt_at <= NOW()
+			  AND (claimed_until IS NULL OR claimed_until < NOW())
+			  AND (o.partition_key = '' OR NOT EXISTS (
+				SELECT 1
+				FROM outbox_events earlier
+				WHERE earlier.partition_key = o.partition_key
+				  AND earlier.status = $4
+				  AND earlier.id < o.id
+			  ))
+			ORDER BY created_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, aggregate_type, aggregate_id, event_type, partition_key, payload, status, attempts, COALESCE(last_error, ''), created_at
+	`
+
+	claimed, err := r.query(ctx, query, workerID, lease.Milliseconds(), limit, StatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+
+	// RETURNING 은 순서를 보장하지 않으므로 생성 순서로 정렬
+	sort.Slice(claimed, func(i, j int) bool { return claimed[i].ID < claimed[j].ID })
+
+	return claimed, nil
+}
+
+// MarkSent 전송 완료 표시
+func (r *repository) MarkSent(ctx context.Context, id int64) error {
+	query := `
+		UPDATE outbox_events
+		SET status = $2, sent_at = NOW(), claimed_by = NULL, claimed_until = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, StatusSent); err != nil {
+		return fmt.Errorf("failed to mark event as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkSentBatch 여러 이벤트를 한 번에 전송 완료 표시
+func (r *repository) MarkSentBatch(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE outbox_events
+		SET status = $2, sent_at = NOW(), claimed_by = NULL, claimed_until = NULL
+		WHERE id = ANY($1)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(ids), StatusSent); err != nil {
+		return fmt.Errorf("failed to mark events as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkRetry 발행 실패 기록 후 delay 뒤에 다시 시도하도록 점유 해제
+func (r *repository) MarkRetry(ctx context.Context, id int64, lastErr string, delay time.Duration) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1,
+		    last_error = $2,
+		    next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond',
+		    claimed_by = NULL,
+		    claimed_until = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, lastErr, delay.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to mark event for retry: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed 최대 시도 횟수를 넘긴 이벤트를 FAILED 로 표시 (재큐잉하기 전까지 발행하지 않음)
+func (r *repository) MarkFailed(ctx context.Context, id int64, lastErr string) error {
+	query := `
+		UPDATE outbox_events
+		SET status = $3,
+		    attempts = attempts + 1,
+		    last_error = $2,
+		    claimed_by = NULL,
+		    claimed_until = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, lastErr, StatusFailed); err != nil {
+		return fmt.Errorf("failed to mark event as failed: %w", err)
+	}
+	return nil
+}
+
+// Get ID로 이벤트 조회
+func (r *repository) Get(ctx context.Context, id int64) (*Event, error) {
+	query := `
+		SELECT id, aggregate_type, aggregate_id, event_type, partition_key, payload, status, attempts, COALESCE(last_error, ''), created_at
+		FROM outbox_events
+		WHERE id = $1
+	`
+
+	found, err := r.query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if len(found) == 0 {
+		return nil, errors.New(errors.ErrCodeNotFound, fmt.Sprintf("outbox event %d not found", id))
+	}
+	return found[0], nil
+}
+
+// FindFailed FAILED 이벤트 목록
+func (r *repository) FindFailed(ctx context.Context, limit int) ([]*Event, error) {
+	query := `
+		SELECT id, aggregate_type, aggregate_id, event_type, partition_key, payload, status, attempts, COALESCE(last_error, ''), created_at
+		FROM outbox_events
+		WHERE status = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	failed, err := r.query(ctx, query, StatusFailed, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find failed events: %w", err)
+	}
+	return failed, nil
+}
+
+// Requeue FAILED 이벤트를 PENDING 으로 되돌림 (시도 횟수 초기화, 즉시 발행 대상)
+func (r *repository) Requeue(ctx context.Context, id int64) error {
+	query := `
+		UPDATE outbox_events
+		SET status = $2, attempts = 0, last_error = NULL, next_attempt_at = NOW(),
+		    claimed_by = NULL, claimed_until = NULL
+		WHERE id = $1 AND status = $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, StatusPending, StatusFailed)
+	if err != nil {
+		return fmt.Errorf("failed to requeue event: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, fmt.Sprintf("failed outbox event %d not found", id))
+	}
+	return nil
+}
+
+// RequeueFailed 모든 FAILED 이벤트를 PENDING 으로 되돌림
+func (r *repository) RequeueFailed(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE outbox_events
+		SET status = $1, attempts = 0, last_error = NULL, next_attempt_at = NOW(),
+		    claimed_by = NULL, claimed_until = NULL
+		WHERE status = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, StatusPending, StatusFailed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue failed events: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// PurgeSent 오래된 SENT 이벤트 삭제
+func (r *repository) PurgeSent(ctx context.Context, olderThan time.Duration, limit int) (int64, error) {
+	query := `
+		DELETE FROM outbox_events
+		WHERE id IN (
+			SELECT id
+			FROM outbox_events
+			WHERE status = $1
+			  AND sent_at < NOW() - $2 * INTERVAL '1 millisecond'
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, StatusSent, olderThan.Milliseconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge sent events: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// ArchiveSent 오래된 SENT 이벤트를 outbox_events_archive 로 이동 (삭제와 삽입이 한 문장에서 원자적으로 처리됨)
+func (r *repository) ArchiveSent(ctx context.Context, olderThan time.Duration, limit int) (int64, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM outbox_events
+			WHERE id IN (
+				SELECT id
+				FROM outbox_events
+				WHERE status = $1
+				  AND sent_at < NOW() - $2 * INTERVAL '1 millisecond'
+				ORDER BY id
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, aggregate_type, aggregate_id, event_type, partition_key, payload, attempts, created_at, sent_at
+		)
+		INSERT INTO outbox_events_archive
+			(id, aggregate_type, aggregate_id, event_type, partition_key, payload, attempts, created_at, sent_at)
+		SELECT id, aggregate_type, aggregate_id, event_type, partition_key, payload, attempts, created_at, sent_at
+		FROM moved
+	`
+
+	result, err := r.db.ExecContext(ctx, query, StatusSent, olderThan.Milliseconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive sent events: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// EnsureArchivePartition 월 단위 아카이브 파티션 생성 (outbox_events_archive_YYYYMM)
+//
+// 파티션이 없는 달의 행은 기본 파티션(outbox_events_archive_default)으로 들어간다.
+// 오래된 아카이브는 해당 월 파티션을 DROP 해 한 번에 정리한다.
+func (r *repository) EnsureArchivePartition(ctx context.Context, month time.Time) error {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	// 파티션 이름과 경계는 날짜에서 만든 값이므로 SQL 인젝션 위험이 없다 (DDL은 파라미터 바인딩 불가)
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS outbox_events_archive_%s
+		PARTITION OF outbox_events_archive
+		FOR VALUES FROM ('%s') TO ('%s')
+	`, from.Format("200601"), from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create archive partition %s: %w", from.Format("200601"), err)
+	}
+	return nil
+}
+
+func (r *repository) query(ctx context.Context, query string, args ...interface{}) ([]*Event, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make([]*Event, 0)
+	for rows.Next() {
+		event := &Event{}
+		err := rows.Scan(
+			&event.ID,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.EventType,
+			&event.PartitionKey,
+			&event.Payload,
+			&event.Status,
+			&event.Attempts,
+			&event.LastError,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
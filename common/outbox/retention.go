@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RetentionConfig 전송 완료 이벤트 보존 설정
+type RetentionConfig struct {
+	// Interval 정리 주기
+	Interval time.Duration
+	// MaxAge 전송 후 outbox_events 에 남겨 둘 기간
+	MaxAge time.Duration
+	// BatchSize 한 문장에서 옮기거나 지울 최대 행 수 (잠금 시간과 WAL 크기를 제한)
+	BatchSize int
+	// MaxBatches 한 번 실행에서 처리할 최대 배치 수 (남은 행은 다음 주기에 처리)
+	MaxBatches int
+	// Archive true 면 outbox_events_archive 로 옮기고, false 면 삭제
+	Archive bool
+}
+
+// DefaultRetentionConfig 기본 보존 설정 (7일 보관 후 아카이브, 1시간마다 최대 100만 건)
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Interval:   time.Hour,
+		MaxAge:     7 * 24 * time.Hour,
+		BatchSize:  1000,
+		MaxBatches: 1000,
+		Archive:    true,
+	}
+}
+
+// RetentionWorker 오래된 SENT 이벤트를 주기적으로 아카이브하거나 삭제하는 워커
+type RetentionWorker struct {
+	repo   Repository
+	config RetentionConfig
+	logger *zap.Logger
+}
+
+// NewRetentionWorker 보존 워커 생성
+func NewRetentionWorker(repo Repository, config RetentionConfig, logger *zap.Logger) *RetentionWorker {
+	return &RetentionWorker{
+		repo:   repo,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Start 워커 시작 (기동 직후 한 번 실행 후 주기적으로 실행, ctx 취소 시 종료)
+func (w *RetentionWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.logger.Info("outbox retention worker started",
+		zap.Duration("interval", w.config.Interval),
+		zap.Duration("maxAge", w.config.MaxAge),
+		zap.Bool("archive", w.config.Archive))
+
+	for {
+		if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Error("failed to purge outbox events", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("outbox retention worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 보존 기간이 지난 SENT 이벤트를 배치 단위로 정리하고 처리한 행 수 반환
+func (w *RetentionWorker) RunOnce(ctx context.Context) (int64, error) {
+	start := time.Now()
+
+	if w.config.Archive {
+		if err := w.ensurePartitions(ctx, start); err != nil {
+			return 0, err
+		}
+	}
+
+	var total int64
+	for batch := 0; batch < w.config.MaxBatches; batch++ {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, err := w.purgeBatch(ctx)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(w.config.BatchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.logger.Info("outbox events purged",
+			zap.Int64("count", total),
+			zap.Bool("archived", w.config.Archive),
+			zap.Duration("elapsed", time.Since(start)))
+	}
+	return total, nil
+}
+
+func (w *RetentionWorker) purgeBatch(ctx context.Context) (int64, error) {
+	if w.config.Archive {
+		return w.repo.ArchiveSent(ctx, w.config.MaxAge, w.config.BatchSize)
+	}
+	return w.repo.PurgeSent(ctx, w.config.MaxAge, w.config.BatchSize)
+}
+
+// ensurePartitions 이번 실행과 다음 주기에 옮길 행이 들어갈 월 파티션을 미리 생성
+//
+// 아카이브되는 행은 sent_at 이 (지금 - MaxAge) 이전이므로 그 달과 다음 달 파티션을 준비한다.
+func (w *RetentionWorker) ensurePartitions(ctx context.Context, now time.Time) error {
+	boundary := now.Add(-w.config.MaxAge).UTC()
+	for _, month := range []time.Time{boundary, boundary.AddDate(0, 1, 0)} {
+		if err := w.repo.EnsureArchivePartition(ctx, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"github.com/kyungseok/msa-saga-go-examples/common/retry"
+	"go.uber.org/zap"
+)
+
+// WorkerConfig Outbox 워커 설정
+type WorkerConfig struct {
+	// WorkerID 점유자 식별자 (인스턴스마다 달라야 함, 예: 호스트명-PID)
+	WorkerID string
+	// Interval 폴링 주기
+	Interval time.Duration
+	// BatchSize 한 번에 점유할 이벤트 수
+	BatchSize int
+	// ClaimLease 배치 점유 유지 시간 (워커가 죽으면 이 시간이 지난 뒤 다른 워커가 재점유)
+	//
+	// 배치 발행과 ack 대기가 끝나기 전에 만료되면 다른 워커가 같은 이벤트를 중복 발행할 수 있으므로
+	// 발행자 타임아웃보다 충분히 길게 잡는다.
+	ClaimLease time.Duration
+	// Retry 발행 실패 시 백오프와 최대 시도 횟수
+	Retry retry.Config
+}
+
+// DefaultWorkerConfig 기본 워커 설정 (1초 폴링, 100건 배치, 30초 점유)
+func DefaultWorkerConfig(workerID string) WorkerConfig {
+	return WorkerConfig{
+		WorkerID:   workerID,
+		Interval:   time.Second,
+		BatchSize:  100,
+		ClaimLease: 30 * time.Second,
+		Retry:      DefaultRetryConfig(),
+	}
+}
+
+// Worker Outbox 테이블의 이벤트를 점유해 발행하는 워커
+type Worker struct {
+	repo      Repository
+	publisher messaging.Publisher
+	config    WorkerConfig
+	logger    *zap.Logger
+	wakeup    <-chan struct{}
+}
+
+// WorkerOption Outbox 워커 옵션
+type WorkerOption func(*Worker)
+
+// WithWakeup 신호를 받으면 폴링 주기를 기다리지 않고 바로 점유 (예: Listener.Wakeups())
+//
+// 신호가 유실되어도 폴링이 이어서 처리하므로 Interval 은 보조 주기로 길게 잡을 수 있다.
+func WithWakeup(wakeup <-chan struct{}) WorkerOption {
+	return func(w *Worker) {
+		w.wakeup = wakeup
+	}
+}
+
+// NewWorker Outbox 워커 생성
+func NewWorker(repo Repository, publisher messaging.Publisher, config WorkerConfig, logger *zap.Logger, opts ...WorkerOption) *Worker {
+	w := &Worker{
+		repo:      repo,
+		publisher: publisher,
+		config:    config,
+		logger:    logger,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Start 워커 시작 (ctx 취소 시 종료)
+func (w *Worker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.logger.Info("outbox worker started",
+		zap.Duration("interval", w.config.Interval),
+		zap.String("workerId", w.config.WorkerID),
+		zap.Bool("wakeup", w.wakeup != nil))
+
+	for {
+		// wakeup 이 없으면 nil 채널이므로 폴링만 동작
+		select {
+		case <-ctx.Done():
+			w.logger.Info("outbox worker stopped")
+			return
+		case <-w.wakeup:
+		case <-ticker.C:
+		}
+
+		if err := w.drain(ctx); err != nil {
+			w.logger.Error("failed to process outbox events", zap.Error(err))
+		}
+	}
+}
+
+// drain 점유할 이벤트가 배치 크기보다 적게 남을 때까지 반복 처리
+func (w *Worker) drain(ctx context.Context) error {
+	for ctx.Err() == nil {
+		claimed, err := w.process(ctx)
+		if err != nil {
+			return err
+		}
+		if claimed < w.config.BatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// process 배치 하나를 점유해 발행하고 점유한 이벤트 수 반환
+func (w *Worker) process(ctx context.Context) (int, error) {
+	// Pending 상태의 이벤트를 점유 (다른 인스턴스가 점유 중인 이벤트는 건너뜀)
+	pending, err := w.repo.Claim(ctx, w.config.WorkerID, w.config.BatchSize, w.config.ClaimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	w.logger.Info("processing outbox events", zap.Int("count", len(pending)))
+
+	// 비동기 발행자면 배치 전체를 한 번에 전송하고 ack 도착 시 전송 완료 표시
+	if publisher, ok := w.publisher.(messaging.AsyncPublishing); ok {
+		w.publishAsync(ctx, publisher, pending)
+		return len(pending), nil
+	}
+
+	for _, event := range pending {
+		if err := w.publishEvent(ctx, event); err != nil {
+			w.recordFailure(ctx, event, err)
+			continue
+		}
+
+		// 전송 완료 표시
+		if err := w.repo.MarkSent(ctx, event.ID); err != nil {
+			w.logger.Error("failed to mark event as sent",
+				zap.Int64("eventId", event.ID),
+				zap.Error(err))
+		}
+	}
+
+	return len(pending), nil
+}
+
+// publishAsync 배치를 발행 큐에 넣고 ack 를 모두 받은 뒤 결과를 한 번에 기록
+//
+// 콜백은 발행자의 ack 수신 고루틴에서 실행되므로 DB 쓰기 없이 결과만 모으고, 전송 완료 표시는 배치 UPDATE 한 번으로 처리한다.
+func (w *Worker) publishAsync(ctx context.Context, publisher messaging.AsyncPublishing, pending []*Event) {
+	// 종료 중에 도착한 ack 도 기록되도록 취소되지 않는 컨텍스트 사용
+	markCtx := context.WithoutCancel(ctx)
+
+	type deliveryFailure struct {
+		event *Event
+		err   error
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sent     []int64
+		failures []deliveryFailure
+	)
+	for _, event := range pending {
+		key, headers, err := prepareEvent(event)
+		if err != nil {
+			w.recordFailure(markCtx, event, err)
+			continue
+		}
+
+		event := event
+		wg.Add(1)
+		err = publisher.PublishAsync(ctx, event.EventType, key, headers, encodedPayload(event), func(result messaging.DeliveryResult) {
+			defer wg.Done()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if result.Err != nil {
+				failures = append(failures, deliveryFailure{event: event, err: result.Err})
+				return
+			}
+			sent = append(sent, event.ID)
+		})
+		if err != nil {
+			wg.Done()
+			w.recordFailure(markCtx, event, err)
+		}
+	}
+
+	// 점유가 만료되기 전에 배치의 ack 를 모두 기다림
+	wg.Wait()
+
+	if err := w.repo.MarkSentBatch(markCtx, sent); err != nil {
+		w.logger.Error("failed to mark events as sent",
+			zap.Int("count", len(sent)),
+			zap.Error(err))
+	}
+	for _, failure := range failures {
+		w.recordFailure(markCtx, failure.event, failure.err)
+	}
+}
+
+func (w *Worker) publishEvent(ctx context.Context, event *Event) error {
+	key, headers, err := prepareEvent(event)
+	if err != nil {
+		return err
+	}
+
+	// Kafka 토픽으로 발행
+	return w.publisher.PublishWithHeaders(ctx, event.EventType, key, headers, encodedPayload(event))
+}
+
+// prepareEvent 이벤트의 파티션 키와 헤더 생성
+func prepareEvent(event *Event) (string, messaging.Headers, error) {
+	// BaseEvent 메타데이터를 헤더로 전달
+	var base events.BaseEvent
+	if err := json.Unmarshal(event.Payload, &base); err != nil {
+		return "", nil, err
+	}
+
+	// 저장 시점에 정한 파티션 키(주문 ID)로 발행해 같은 SAGA의 이벤트 순서를 보장
+	return event.PartitionKey, messaging.EventHeaders(base), nil
+}
+
+// encodedPayload 저장된 JSON 페이로드를 발행자 코덱과 무관하게 그대로 발행하도록 감쌈
+func encodedPayload(event *Event) messaging.Encoded {
+	return messaging.Encoded{ContentType: messaging.ContentTypeJSON, Data: event.Payload}
+}
+
+// recordFailure 발행 실패 기록 (지수 백오프 후 재시도, 최대 시도 횟수에 도달하면 FAILED)
+func (w *Worker) recordFailure(ctx context.Context, event *Event, cause error) {
+	attempts := event.Attempts + 1
+	if attempts >= w.config.Retry.MaxAttempts {
+		w.logger.Error("outbox event failed permanently",
+			zap.Int64("eventId", event.ID),
+			zap.String("eventType", event.EventType),
+			zap.Int("attempts", attempts),
+			zap.Error(cause))
+		if err := w.repo.MarkFailed(ctx, event.ID, cause.Error()); err != nil {
+			w.logger.Error("failed to mark event as failed",
+				zap.Int64("eventId", event.ID),
+				zap.Error(err))
+		}
+		return
+	}
+
+	delay := w.config.Retry.Backoff(attempts)
+	w.logger.Warn("failed to publish event",
+		zap.Int64("eventId", event.ID),
+		zap.String("eventType", event.EventType),
+		zap.Int("attempts", attempts),
+		zap.Duration("retryIn", delay),
+		zap.Error(cause))
+	if err := w.repo.MarkRetry(ctx, event.ID, cause.Error(), delay); err != nil {
+		w.logger.Error("failed to record publish failure",
+			zap.Int64("eventId", event.ID),
+			zap.Error(err))
+	}
+}
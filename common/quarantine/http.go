@@ -0,0 +1,211 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"go.uber.org/zap"
+)
+
+// AdminPath 관리 API 경로 (mux.Handle(AdminPath, ...) 와 mux.Handle(AdminPath+"/", ...) 로 등록)
+const AdminPath = "/admin/quarantine"
+
+// maxPayloadSize 수정 요청 본문 최대 크기
+const maxPayloadSize = 1 << 20
+
+// AdminHandler 격리 메시지 관리 API
+//
+//	GET  /admin/quarantine?status=QUARANTINED&limit=50  목록
+//	GET  /admin/quarantine/{id}                         조회 (원본 페이로드 포함)
+//	PUT  /admin/quarantine/{id}                         페이로드 수정 (본문 = 새 JSON 페이로드)
+//	POST /admin/quarantine/{id}/reinject                원래 토픽으로 재발행
+type AdminHandler struct {
+	store     Store
+	publisher messaging.Publisher
+	logger    *zap.Logger
+}
+
+// NewAdminHandler 격리 메시지 관리 API 생성
+func NewAdminHandler(store Store, publisher messaging.Publisher, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		store:     store,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// recordView 조회 응답 (JSON 페이로드는 그대로, 아니면 문자열로 표시)
+type recordView struct {
+	*Record
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	RawPayload string          `json:"rawPayload,omitempty"`
+}
+
+func newRecordView(record *Record) recordView {
+	view := recordView{Record: record}
+	if json.Valid(record.Payload) {
+		view.Payload = record.Payload
+	} else {
+		view.RawPayload = string(record.Payload)
+	}
+	return view
+}
+
+// ServeHTTP 경로와 메서드에 따라 요청 분기
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, AdminPath), "/")
+	if path == "" {
+		if r.Method != http.MethodGet {
+			h.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.list(w, r)
+		return
+	}
+
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid quarantine ID")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.get(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.fix(w, r, id)
+	case action == "reinject" && r.Method == http.MethodPost:
+		h.reinject(w, r, id)
+	case action == "" || action == "reinject":
+		h.respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		h.respondError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *AdminHandler) list(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 500 {
+			h.respondError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = n
+	}
+
+	records, err := h.store.List(r.Context(), r.URL.Query().Get("status"), limit)
+	if err != nil {
+		h.logger.Error("failed to list quarantined messages", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "failed to list quarantined messages")
+		return
+	}
+
+	views := make([]recordView, 0, len(records))
+	for _, record := range records {
+		views = append(views, newRecordView(record))
+	}
+	h.respondJSON(w, http.StatusOK, views)
+}
+
+func (h *AdminHandler) get(w http.ResponseWriter, r *http.Request, id int64) {
+	record, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		h.respondStoreError(w, err)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, newRecordView(record))
+}
+
+func (h *AdminHandler) fix(w http.ResponseWriter, r *http.Request, id int64) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil || len(payload) > maxPayloadSize {
+		h.respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// 재발행은 JSON 코덱으로 하므로 수정본은 유효한 JSON 이어야 한다
+	if !json.Valid(payload) {
+		h.respondError(w, http.StatusBadRequest, "payload must be valid JSON")
+		return
+	}
+
+	if err := h.store.UpdatePayload(r.Context(), id, payload); err != nil {
+		h.respondStoreError(w, err)
+		return
+	}
+
+	h.logger.Info("quarantined message fixed", zap.Int64("id", id))
+	h.get(w, r, id)
+}
+
+func (h *AdminHandler) reinject(w http.ResponseWriter, r *http.Request, id int64) {
+	ctx := r.Context()
+
+	record, err := h.store.Get(ctx, id)
+	if err != nil {
+		h.respondStoreError(w, err)
+		return
+	}
+	if record.Status != StatusQuarantined {
+		h.respondError(w, http.StatusConflict, "message already reinjected")
+		return
+	}
+	if !json.Valid(record.Payload) {
+		h.respondError(w, http.StatusUnprocessableEntity, "payload is not valid JSON; fix it before reinjecting")
+		return
+	}
+
+	// 원본 헤더(이벤트 ID 등)를 유지해 컨슈머의 멱등성 처리와 추적이 이어지도록 한다
+	headers := make(messaging.Headers, len(record.Headers))
+	for k, v := range record.Headers {
+		if k != messaging.HeaderContentType {
+			headers[k] = v
+		}
+	}
+
+	if err := h.publisher.PublishWithHeaders(ctx, record.Topic, record.Key, headers,
+		messaging.Encoded{ContentType: messaging.ContentTypeJSON, Data: record.Payload}); err != nil {
+		h.logger.Error("failed to reinject quarantined message", zap.Int64("id", id), zap.Error(err))
+		h.respondError(w, http.StatusBadGateway, "failed to publish message")
+		return
+	}
+
+	if err := h.store.MarkReinjected(ctx, id); err != nil {
+		h.respondStoreError(w, err)
+		return
+	}
+
+	h.logger.Info("quarantined message reinjected",
+		zap.Int64("id", id),
+		zap.String("topic", record.Topic),
+		zap.String("key", record.Key))
+	h.get(w, r, id)
+}
+
+func (h *AdminHandler) respondStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.IsCode(err, errors.ErrCodeNotFound):
+		h.respondError(w, http.StatusNotFound, err.Error())
+	case errors.IsCode(err, errors.ErrCodeConflict):
+		h.respondError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("quarantine store failed", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func (h *AdminHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AdminHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,242 @@
+package quarantine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+)
+
+// 격리 메시지 상태
+const (
+	StatusQuarantined = "QUARANTINED"
+	StatusReinjected  = "REINJECTED"
+)
+
+// Record 격리된 메시지
+type Record struct {
+	ID           int64             `json:"id"`
+	Topic        string            `json:"topic"`
+	Partition    int32             `json:"partition"`
+	Position     string            `json:"position"`
+	Offset       int64             `json:"offset"`
+	Key          string            `json:"key"`
+	Payload      []byte            `json:"-"`
+	Headers      messaging.Headers `json:"headers"`
+	Error        string            `json:"error"`
+	Status       string            `json:"status"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	ReinjectedAt *time.Time        `json:"reinjectedAt,omitempty"`
+}
+
+// Store 격리 메시지 저장소 인터페이스
+type Store interface {
+	messaging.Quarantine
+	// List 상태별 격리 메시지 목록 (status 가 빈 값이면 전체, 최신순)
+	List(ctx context.Context, status string, limit int) ([]*Record, error)
+	// Get 격리 메시지 조회 (없으면 ErrCodeNotFound)
+	Get(ctx context.Context, id int64) (*Record, error)
+	// UpdatePayload 재발행 전 페이로드 수정 (격리 상태인 메시지만)
+	UpdatePayload(ctx context.Context, id int64, payload []byte) error
+	// MarkReinjected 재발행 완료 표시
+	MarkReinjected(ctx context.Context, id int64) error
+}
+
+// PostgresStore PostgreSQL 기반 격리 메시지 저장소 (quarantined_messages 테이블)
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore PostgreSQL 기반 격리 메시지 저장소 생성
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Quarantine 디코딩 실패 메시지 보관
+//
+// 같은 위치(토픽/파티션/Message.Position)의 메시지가 재전달되어도 한 번만 보관한다.
+// 같은 위치에 다른 메시지가 이미 보관되어 있으면 원본을 잃지 않도록 에러를 반환한다.
+func (s *PostgresStore) Quarantine(ctx context.Context, msg *messaging.Message, reason error) error {
+	headers, err := json.Marshal(msg.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message headers: %w", err)
+	}
+	fingerprint := sha256.Sum256(msg.Value)
+
+	query := `
+		INSERT INTO quarantined_messages
+			(topic, partition, position, "offset", message_key, payload, payload_sha256, headers, error, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (topic, partition, position) DO NOTHING
+		RETURNING id
+	`
+
+	var id int64
+	err = s.db.QueryRowContext(ctx, query,
+		msg.Topic,
+		msg.Partition,
+		msg.Position(),
+		msg.Offset,
+		string(msg.Key),
+		msg.Value,
+		fingerprint[:],
+		headers,
+		reason.Error(),
+		StatusQuarantined,
+	).Scan(&id)
+	if err == nil {
+		return nil
+	}
+	if !stderrors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to insert quarantined message: %w", err)
+	}
+
+	// 충돌한 행이 같은 메시지의 재전달인지 확인 (페이로드는 관리 API로 수정될 수 있으므로 원본 해시로 비교)
+	var storedKey string
+	var storedFingerprint []byte
+	err = s.db.QueryRowContext(ctx, `
+		SELECT message_key, payload_sha256
+		FROM quarantined_messages
+		WHERE topic = $1 AND partition = $2 AND position = $3
+	`, msg.Topic, msg.Partition, msg.Position()).Scan(&storedKey, &storedFingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to look up quarantined message: %w", err)
+	}
+	if storedKey != string(msg.Key) || !bytes.Equal(storedFingerprint, fingerprint[:]) {
+		return fmt.Errorf("quarantine position %s/%d/%s already holds a different message",
+			msg.Topic, msg.Partition, msg.Position())
+	}
+	return nil
+}
+
+// List 상태별 격리 메시지 목록
+func (s *PostgresStore) List(ctx context.Context, status string, limit int) ([]*Record, error) {
+	query := `
+		SELECT id, topic, partition, position, "offset", message_key, payload, headers, error, status, created_at, reinjected_at
+		FROM quarantined_messages
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined messages: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate quarantined messages: %w", err)
+	}
+
+	return records, nil
+}
+
+// Get 격리 메시지 조회
+func (s *PostgresStore) Get(ctx context.Context, id int64) (*Record, error) {
+	query := `
+		SELECT id, topic, partition, position, "offset", message_key, payload, headers, error, status, created_at, reinjected_at
+		FROM quarantined_messages
+		WHERE id = $1
+	`
+
+	record, err := scanRecord(s.db.QueryRowContext(ctx, query, id))
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New(errors.ErrCodeNotFound, fmt.Sprintf("quarantined message %d not found", id))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// UpdatePayload 재발행 전 페이로드 수정
+func (s *PostgresStore) UpdatePayload(ctx context.Context, id int64, payload []byte) error {
+	query := `
+		UPDATE quarantined_messages
+		SET payload = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`
+
+	result, err := s.db.ExecContext(ctx, query, id, payload, StatusQuarantined)
+	if err != nil {
+		return fmt.Errorf("failed to update quarantined message: %w", err)
+	}
+	return s.expectOne(result, id)
+}
+
+// MarkReinjected 재발행 완료 표시
+func (s *PostgresStore) MarkReinjected(ctx context.Context, id int64) error {
+	query := `
+		UPDATE quarantined_messages
+		SET status = $2, reinjected_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`
+
+	result, err := s.db.ExecContext(ctx, query, id, StatusReinjected, StatusQuarantined)
+	if err != nil {
+		return fmt.Errorf("failed to mark quarantined message as reinjected: %w", err)
+	}
+	return s.expectOne(result, id)
+}
+
+// expectOne 갱신된 행이 없으면 이미 재발행됐거나 없는 메시지
+func (s *PostgresStore) expectOne(result sql.Result, id int64) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict,
+			fmt.Sprintf("quarantined message %d not found or already reinjected", id))
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecord(row rowScanner) (*Record, error) {
+	record := &Record{}
+	var headers []byte
+	err := row.Scan(
+		&record.ID,
+		&record.Topic,
+		&record.Partition,
+		&record.Position,
+		&record.Offset,
+		&record.Key,
+		&record.Payload,
+		&headers,
+		&record.Error,
+		&record.Status,
+		&record.CreatedAt,
+		&record.ReinjectedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan quarantined message: %w", err)
+	}
+
+	if err := json.Unmarshal(headers, &record.Headers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message headers: %w", err)
+	}
+	return record, nil
+}
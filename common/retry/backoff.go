@@ -28,6 +28,18 @@ func DefaultConfig() Config {
 	}
 }
 
+// Backoff attempt 번째 실패 후 다음 시도까지 대기 시간 (지수 백오프, MaxInterval 로 제한)
+func (c Config) Backoff(attempt int) time.Duration {
+	interval := c.InitialInterval
+	for i := 1; i < attempt; i++ {
+		interval = time.Duration(float64(interval) * c.BackoffCoefficient)
+		if c.MaxInterval > 0 && interval >= c.MaxInterval {
+			return c.MaxInterval
+		}
+	}
+	return interval
+}
+
 // Do 재시도 실행
 func Do(ctx context.Context, config Config, logger *zap.Logger, fn func() error) error {
 	var lastErr error
@@ -0,0 +1,118 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState 서킷 브레이커 상태
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 정상 호출
+	CircuitOpen                         // 호출 차단
+	CircuitHalfOpen                     // 시험 호출 1건 허용
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig 서킷 브레이커 설정
+type CircuitBreakerConfig struct {
+	// FailureThreshold 연속 실패가 이 횟수에 도달하면 open
+	FailureThreshold int
+	// OpenTimeout open 상태 유지 시간 (지나면 half-open 으로 시험 호출)
+	OpenTimeout time.Duration
+}
+
+// DefaultCircuitBreakerConfig 기본 서킷 브레이커 설정
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// CircuitBreaker 연속 실패 기반 서킷 브레이커
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	config   CircuitBreakerConfig
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker 서킷 브레이커 생성
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+	return &CircuitBreaker{config: config}
+}
+
+// Allow 호출 가능 여부 (차단 중이면 false 와 다시 시도할 때까지 남은 시간)
+//
+// true 를 반환한 호출은 결과를 Success 또는 Failure 로 반드시 알려야 한다.
+func (b *CircuitBreaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		remaining := b.config.OpenTimeout - time.Since(b.openedAt)
+		if remaining > 0 {
+			return false, remaining
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return true, 0
+	case CircuitHalfOpen:
+		// 시험 호출 결과가 나올 때까지 나머지는 차단
+		if b.probing {
+			return false, b.config.OpenTimeout
+		}
+		b.probing = true
+		return true, 0
+	}
+	return true, 0
+}
+
+// Success 호출 성공 기록 (half-open 이면 closed 로 복귀)
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure 호출 실패 기록 (임계치 도달 또는 half-open 시험 실패 시 open)
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State 현재 상태
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -43,14 +43,68 @@ CREATE TABLE IF NOT EXISTS outbox_events (
     aggregate_type VARCHAR(50) NOT NULL,
     aggregate_id BIGINT NOT NULL,
     event_type VARCHAR(50) NOT NULL,
+    -- 발행 파티션 키 (주문 ID, 같은 SAGA의 이벤트를 같은 파티션에 순서대로 발행)
+    partition_key VARCHAR(64) NOT NULL DEFAULT '',
     payload JSONB NOT NULL,
     status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    sent_at TIMESTAMPTZ
+    sent_at TIMESTAMPTZ,
+    -- 발행 재시도 (지수 백오프, 최대 횟수를 넘기면 FAILED)
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- 발행 워커 점유 (여러 인스턴스가 같은 이벤트를 발행하지 않도록, 만료되면 다른 워커가 재점유)
+    claimed_by VARCHAR(128),
+    claimed_until TIMESTAMPTZ
 );
 
 CREATE INDEX idx_outbox_events_status_created_at ON outbox_events(status, created_at);
 CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
+CREATE INDEX idx_outbox_events_next_attempt ON outbox_events(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_outbox_events_partition_key ON outbox_events(partition_key, id) WHERE status = 'PENDING';
+CREATE INDEX idx_outbox_events_sent_at ON outbox_events(sent_at) WHERE status = 'SENT';
+
+-- 새 Outbox 이벤트 알림 (커밋 시점에 전달되어 발행 워커가 폴링을 기다리지 않고 바로 발행)
+CREATE OR REPLACE FUNCTION notify_outbox_events() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_outbox_events_notify
+    AFTER INSERT ON outbox_events
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_outbox_events();
+
+-- CDC 발행 (OUTBOX_RELAY=cdc): 릴레이가 논리 복제 슬롯으로 읽는 publication 과 발행을 마친 위치
+CREATE PUBLICATION outbox_events_pub FOR TABLE outbox_events WITH (publish = 'insert, update');
+
+CREATE TABLE IF NOT EXISTS outbox_relay_checkpoints (
+    slot_name VARCHAR(64) PRIMARY KEY,
+    lsn PG_LSN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 보존 기간이 지난 SENT 이벤트 아카이브 (sent_at 기준 월 단위 파티션, 보존 워커가 파티션을 미리 생성)
+-- 오래된 아카이브는 해당 월 파티션을 DROP 해 정리
+CREATE TABLE IF NOT EXISTS outbox_events_archive (
+    id BIGINT NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    partition_key VARCHAR(64) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, sent_at)
+) PARTITION BY RANGE (sent_at);
+
+-- 월 파티션이 없는 행이 들어가는 기본 파티션
+CREATE TABLE IF NOT EXISTS outbox_events_archive_default PARTITION OF outbox_events_archive DEFAULT;
+
+CREATE INDEX idx_outbox_events_archive_aggregate ON outbox_events_archive(aggregate_type, aggregate_id);
 
 -- SAGA 상태 추적 테이블
 CREATE TABLE IF NOT EXISTS saga_instances (
@@ -68,10 +122,33 @@ CREATE TABLE IF NOT EXISTS saga_instances (
 CREATE INDEX idx_saga_instances_order_id ON saga_instances(order_id);
 CREATE INDEX idx_saga_instances_status ON saga_instances(status);
 
+-- 격리 메시지 테이블 (디코딩할 수 없는 수신 메시지 보관, 수정 후 재발행)
+CREATE TABLE IF NOT EXISTS quarantined_messages (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    partition INT NOT NULL,
+    position VARCHAR(64) NOT NULL,
+    "offset" BIGINT NOT NULL,
+    message_key VARCHAR(255) NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    payload_sha256 BYTEA NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'QUARANTINED',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reinjected_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_quarantined_messages_position ON quarantined_messages(topic, partition, position);
+CREATE INDEX idx_quarantined_messages_status ON quarantined_messages(status, id DESC);
+
 -- 샘플 데이터 (테스트용)
 -- 실제 운영에서는 제거하거나 주석 처리
 
 COMMENT ON TABLE orders IS '주문 테이블';
 COMMENT ON TABLE outbox_events IS 'Outbox 패턴을 위한 이벤트 테이블';
 COMMENT ON TABLE saga_instances IS 'SAGA 인스턴스 추적 테이블';
-
+COMMENT ON TABLE quarantined_messages IS '디코딩 실패로 격리된 메시지 테이블';
+COMMENT ON TABLE outbox_events_archive IS '보존 기간이 지난 Outbox 이벤트 아카이브 테이블';
+COMMENT ON TABLE outbox_relay_checkpoints IS 'CDC 릴레이 발행 위치(LSN) 테이블';
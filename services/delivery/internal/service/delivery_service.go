@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kyungseok/msa-saga-go-examples/common/errors"
 	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
 	"github.com/kyungseok/msa-saga-go-examples/services/delivery/internal/domain"
 	"github.com/kyungseok/msa-saga-go-examples/services/delivery/internal/repository"
 	"go.uber.org/zap"
@@ -20,14 +21,14 @@ type DeliveryService interface {
 
 type deliveryService struct {
 	deliveryRepo repository.DeliveryRepository
-	outboxRepo   repository.OutboxRepository
+	outboxRepo   outbox.Repository
 	logger       *zap.Logger
 }
 
 // NewDeliveryService 배송 서비스 생성
 func NewDeliveryService(
 	deliveryRepo repository.DeliveryRepository,
-	outboxRepo repository.OutboxRepository,
+	outboxRepo outbox.Repository,
 	logger *zap.Logger,
 ) DeliveryService {
 	return &deliveryService{
@@ -86,13 +87,14 @@ func (s *deliveryService) HandleStockReserved(ctx context.Context, evt events.St
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID:    evt.OrderID,
 		DeliveryID: delivery.ID,
 		Address:    address,
 	}
 
-	if err := s.outboxRepo.InsertEvent(ctx, tx, "delivery", delivery.ID, deliveryStartedEvt); err != nil {
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "delivery", ID: delivery.ID}, deliveryStartedEvt); err != nil {
 		return err
 	}
 
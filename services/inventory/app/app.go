@@ -0,0 +1,62 @@
+// Package app 재고 서비스 구성 요소 조립
+//
+// cmd/main.go 와 네 서비스를 한 프로세스에서 메모리 브로커로 묶는 services/local 이 함께 사용한다.
+// 메시징 백엔드, Outbox 발행, HTTP 서버 같은 실행 환경은 호출자가 구성한다.
+package app
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
+	"github.com/kyungseok/msa-saga-go-examples/common/quarantine"
+	"github.com/kyungseok/msa-saga-go-examples/services/inventory/internal/repository"
+	"github.com/kyungseok/msa-saga-go-examples/services/inventory/internal/service"
+	"go.uber.org/zap"
+)
+
+// App 재고 서비스
+type App struct {
+	// OutboxRepo 재고 서비스 Outbox (발행 워커, 보존 워커, 관리 API 에서 사용)
+	OutboxRepo outbox.Repository
+	// Quarantine 디코딩 실패 메시지 격리 저장소
+	Quarantine *quarantine.PostgresStore
+
+	router *messaging.Router
+}
+
+// New 재고 서비스 조립
+func New(db *sql.DB, idemStore idempotency.Store, logger *zap.Logger) *App {
+	inventoryRepo := repository.NewInventoryRepository(db)
+	reservationRepo := repository.NewStockReservationRepository(db)
+	outboxRepo := outbox.NewRepository(db)
+	quarantineStore := quarantine.NewPostgresStore(db)
+
+	inventoryService := service.NewInventoryService(inventoryRepo, reservationRepo, outboxRepo, logger)
+
+	router := messaging.NewRouter(logger,
+		messaging.WithIdempotency(idemStore, 24*time.Hour),
+		messaging.WithQuarantine(quarantineStore))
+	messaging.On[events.PaymentCompletedEvent](router, inventoryService.HandlePaymentCompleted)
+	messaging.On[events.PaymentRefundedEvent](router, inventoryService.HandlePaymentRefunded)
+
+	return &App{
+		OutboxRepo: outboxRepo,
+		Quarantine: quarantineStore,
+		router:     router,
+	}
+}
+
+// Topics 구독할 토픽 목록
+func (a *App) Topics() []string {
+	return a.router.Topics()
+}
+
+// HandleMessage 메시지 처리
+func (a *App) HandleMessage(ctx context.Context, msg *messaging.Message) error {
+	return a.router.Handle(ctx, msg)
+}
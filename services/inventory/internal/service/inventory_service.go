@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kyungseok/msa-saga-go-examples/common/errors"
 	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
 	"github.com/kyungseok/msa-saga-go-examples/services/inventory/internal/domain"
 	"github.com/kyungseok/msa-saga-go-examples/services/inventory/internal/repository"
 	"go.uber.org/zap"
@@ -23,7 +24,7 @@ type InventoryService interface {
 type inventoryService struct {
 	inventoryRepo    repository.InventoryRepository
 	reservationRepo  repository.StockReservationRepository
-	outboxRepo       repository.OutboxRepository
+	outboxRepo       outbox.Repository
 	logger           *zap.Logger
 }
 
@@ -31,7 +32,7 @@ type inventoryService struct {
 func NewInventoryService(
 	inventoryRepo repository.InventoryRepository,
 	reservationRepo repository.StockReservationRepository,
-	outboxRepo repository.OutboxRepository,
+	outboxRepo outbox.Repository,
 	logger *zap.Logger,
 ) InventoryService {
 	return &inventoryService{
@@ -113,13 +114,14 @@ func (s *inventoryService) HandlePaymentCompleted(ctx context.Context, evt event
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID:       evt.OrderID,
 		ReservationID: reservation.ID,
 		Quantity:      quantity,
 	}
 
-	if err := s.outboxRepo.InsertEvent(ctx, tx, "stock_reservation", reservation.ID, stockReservedEvt); err != nil {
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "stock_reservation", ID: reservation.ID}, stockReservedEvt); err != nil {
 		return err
 	}
 
@@ -187,13 +189,14 @@ func (s *inventoryService) HandlePaymentRefunded(ctx context.Context, evt events
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID:       evt.OrderID,
 		ReservationID: reservation.ID,
 		Quantity:      reservation.Quantity,
 	}
 
-	if err := s.outboxRepo.InsertEvent(ctx, tx, "stock_reservation", reservation.ID, stockRestoredEvt); err != nil {
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "stock_reservation", ID: reservation.ID}, stockRestoredEvt); err != nil {
 		return err
 	}
 
@@ -222,13 +225,14 @@ func (s *inventoryService) publishStockReservationFailed(
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID:  evt.OrderID,
 		Quantity: 1,
 		Reason:   reason,
 	}
 
-	if err := s.outboxRepo.InsertEvent(ctx, tx, "order", evt.OrderID, failedEvt); err != nil {
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "order", ID: evt.OrderID}, failedEvt); err != nil {
 		return err
 	}
 
@@ -240,5 +244,6 @@ func (s *inventoryService) publishStockReservationFailed(
 		zap.Int64("orderId", evt.OrderID),
 		zap.String("reason", reason))
 
-	return fmt.Errorf("stock reservation failed: %s", reason)
+	// 재고 부족은 보상 이벤트로 처리가 끝난 것이므로 멱등성 처리 완료로 기록되도록 성공으로 반환
+	return nil
 }
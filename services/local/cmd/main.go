@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/logger"
+	"github.com/kyungseok/msa-saga-go-examples/services/local"
+)
+
+// 네 서비스를 메모리 브로커로 연결해 한 프로세스에서 실행 (Kafka, Redis 불필요)
+func main() {
+	log, err := logger.NewLogger("local-saga", true)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer log.Sync()
+
+	defaults := local.DefaultConfig()
+	config := defaults
+	config.OrderDSN = getEnv("ORDER_DB_DSN", defaults.OrderDSN)
+	config.PaymentDSN = getEnv("PAYMENT_DB_DSN", defaults.PaymentDSN)
+	config.InventoryDSN = getEnv("INVENTORY_DB_DSN", defaults.InventoryDSN)
+	config.DeliveryDSN = getEnv("DELIVERY_DB_DSN", defaults.DeliveryDSN)
+	config.Partitions = getEnvInt("MEMORY_PARTITIONS", defaults.Partitions)
+	servicePort := getEnv("SERVICE_PORT", "8000")
+
+	stack, err := local.Start(context.Background(), config, log)
+	if err != nil {
+		log.Fatal("failed to start local saga stack", zap.Error(err))
+	}
+
+	server := &http.Server{
+		Addr:    ":" + servicePort,
+		Handler: stack.OrderAPI,
+	}
+
+	go func() {
+		log.Info("http server starting", zap.String("port", servicePort))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("http server failed", zap.Error(err))
+		}
+	}()
+
+	// Graceful Shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("shutting down server...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error("server forced to shutdown", zap.Error(err))
+	}
+
+	stack.Close()
+	log.Info("server stopped")
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,167 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/kyungseok/msa-saga-go-examples/services/local"
+)
+
+// 실제 네 서비스를 메모리 브로커로 연결해 SAGA 를 끝까지 실행
+// 서비스별 PostgreSQL 이 필요하므로 ORDER_DB_DSN 이 지정된 경우에만 실행 (나머지 DSN 은 docker-compose 기본값)
+func startStack(t *testing.T) (*local.Stack, local.Config) {
+	t.Helper()
+	if os.Getenv("ORDER_DB_DSN") == "" {
+		t.Skip("ORDER_DB_DSN not set")
+	}
+
+	config := local.DefaultConfig()
+	config.OrderDSN = os.Getenv("ORDER_DB_DSN")
+	config.PaymentDSN = getEnv("PAYMENT_DB_DSN", config.PaymentDSN)
+	config.InventoryDSN = getEnv("INVENTORY_DB_DSN", config.InventoryDSN)
+	config.DeliveryDSN = getEnv("DELIVERY_DB_DSN", config.DeliveryDSN)
+	config.OutboxInterval = 50 * time.Millisecond
+	config.Retry.Delays = []time.Duration{10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stack, err := local.Start(ctx, config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to start local stack: %v", err)
+	}
+	t.Cleanup(stack.Close)
+	return stack, config
+}
+
+func TestLocalSagaCompletes(t *testing.T) {
+	stack, _ := startStack(t)
+
+	// 결제 게이트웨이가 무작위로 거절하므로 (CANCELED) 완료될 때까지 새 주문으로 재시도
+	for attempt := 0; attempt < 5; attempt++ {
+		orderID := createOrder(t, stack.OrderAPI)
+		status := waitForFinalStatus(t, stack.OrderAPI, orderID)
+		if status == "COMPLETED" {
+			return
+		}
+		if status != "CANCELED" {
+			t.Fatalf("order %d: expected COMPLETED or CANCELED, got %s", orderID, status)
+		}
+	}
+	t.Fatal("no order completed after 5 attempts")
+}
+
+func TestLocalSagaCompensatesOnOutOfStock(t *testing.T) {
+	stack, config := startStack(t)
+
+	inventoryDB := openDB(t, config.InventoryDSN)
+	var stock int
+	if err := inventoryDB.QueryRow(`SELECT available_quantity FROM inventory WHERE product_id = 1`).Scan(&stock); err != nil {
+		t.Fatalf("failed to read stock: %v", err)
+	}
+	if _, err := inventoryDB.Exec(`UPDATE inventory SET available_quantity = 0 WHERE product_id = 1`); err != nil {
+		t.Fatalf("failed to empty stock: %v", err)
+	}
+	t.Cleanup(func() {
+		inventoryDB.Exec(`UPDATE inventory SET available_quantity = $1 WHERE product_id = 1`, stock)
+	})
+
+	paymentDB := openDB(t, config.PaymentDSN)
+
+	// 결제가 거절되면 재고 예약까지 가지 않으므로 결제가 승인된 주문이 나올 때까지 재시도
+	for attempt := 0; attempt < 5; attempt++ {
+		orderID := createOrder(t, stack.OrderAPI)
+		status := waitForFinalStatus(t, stack.OrderAPI, orderID)
+		if status == "CANCELED" {
+			continue
+		}
+		if status != "FAILED" {
+			t.Fatalf("order %d: expected FAILED, got %s", orderID, status)
+		}
+
+		// 보상 트랜잭션: 재고 예약 실패 이벤트를 받은 결제 서비스가 환불
+		deadline := time.Now().Add(30 * time.Second)
+		var paymentStatus string
+		for time.Now().Before(deadline) {
+			err := paymentDB.QueryRow(`SELECT status FROM payments WHERE order_id = $1`, orderID).Scan(&paymentStatus)
+			if err != nil {
+				t.Fatalf("failed to read payment: %v", err)
+			}
+			if paymentStatus == "REFUNDED" {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("order %d: expected payment REFUNDED, got %s", orderID, paymentStatus)
+	}
+	t.Fatal("no order reached stock reservation after 5 attempts")
+}
+
+func createOrder(t *testing.T, api http.Handler) int64 {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"userId": 1, "amount": 10000, "quantity": 1})
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create order: status %d, body %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		OrderID int64 `json:"orderId"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode create order response: %v", err)
+	}
+	return resp.OrderID
+}
+
+// waitForFinalStatus 주문이 COMPLETED, CANCELED, FAILED 중 하나가 될 때까지 조회
+func waitForFinalStatus(t *testing.T, api http.Handler, orderID int64) string {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	var status string
+	for time.Now().Before(deadline) {
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/orders/%d", orderID), nil))
+		if rec.Code == http.StatusOK {
+			var order struct{ Status string }
+			if err := json.Unmarshal(rec.Body.Bytes(), &order); err != nil {
+				t.Fatalf("failed to decode order: %v", err)
+			}
+			status = order.Status
+			switch status {
+			case "COMPLETED", "CANCELED", "FAILED":
+				return status
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("order %d did not finish, last status %q", orderID, status)
+	return ""
+}
+
+func openDB(t *testing.T, dsn string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,70 @@
+// Package app 주문 서비스 구성 요소 조립
+//
+// cmd/main.go 와 네 서비스를 한 프로세스에서 메모리 브로커로 묶는 services/local 이 함께 사용한다.
+// 메시징 백엔드, Outbox 발행, HTTP 서버 같은 실행 환경은 호출자가 구성한다.
+package app
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
+	"github.com/kyungseok/msa-saga-go-examples/common/quarantine"
+	"github.com/kyungseok/msa-saga-go-examples/services/order/internal/handler"
+	"github.com/kyungseok/msa-saga-go-examples/services/order/internal/repository"
+	"github.com/kyungseok/msa-saga-go-examples/services/order/internal/service"
+	"go.uber.org/zap"
+)
+
+// App 주문 서비스
+type App struct {
+	// OutboxRepo 주문 서비스 Outbox (발행 워커, 보존 워커, 관리 API 에서 사용)
+	OutboxRepo outbox.Repository
+	// Quarantine 디코딩 실패 메시지 격리 저장소
+	Quarantine *quarantine.PostgresStore
+
+	idemStore    idempotency.Store
+	eventHandler *handler.EventHandler
+	httpHandler  *handler.HTTPHandler
+	logger       *zap.Logger
+}
+
+// New 주문 서비스 조립
+func New(db *sql.DB, idemStore idempotency.Store, logger *zap.Logger) *App {
+	orderRepo := repository.NewOrderRepository(db)
+	outboxRepo := outbox.NewRepository(db)
+	quarantineStore := quarantine.NewPostgresStore(db)
+
+	orderService := service.NewOrderService(db, orderRepo, outboxRepo, logger)
+
+	return &App{
+		OutboxRepo:   outboxRepo,
+		Quarantine:   quarantineStore,
+		idemStore:    idemStore,
+		eventHandler: handler.NewEventHandler(orderService, idemStore, quarantineStore, logger),
+		httpHandler:  handler.NewHTTPHandler(orderService, logger),
+		logger:       logger,
+	}
+}
+
+// Topics 구독할 토픽 목록
+func (a *App) Topics() []string {
+	return a.eventHandler.Topics()
+}
+
+// HandleMessage 메시지 처리
+func (a *App) HandleMessage(ctx context.Context, msg *messaging.Message) error {
+	return a.eventHandler.HandleMessage(ctx, msg)
+}
+
+// RegisterRoutes 주문 API 등록 (/health, /orders, /orders/{id})
+func (a *App) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/health", a.httpHandler.HealthCheck)
+	// Idempotency-Key 헤더로 재시도한 주문 생성 요청은 처음 응답을 그대로 재생
+	idempotent := idempotency.Middleware(a.idemStore, idempotency.DefaultHTTPConfig(), a.logger)
+	mux.Handle("/orders", idempotent(http.HandlerFunc(a.httpHandler.CreateOrder)))
+	mux.HandleFunc("/orders/", a.httpHandler.GetOrder)
+}
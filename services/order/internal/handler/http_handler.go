@@ -2,10 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"net/http"
 	"strconv"
 
 	"github.com/google/uuid"
+	"github.com/kyungseok/msa-saga-go-examples/common/errors"
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
 	"github.com/kyungseok/msa-saga-go-examples/services/order/internal/service"
 	"go.uber.org/zap"
 )
@@ -57,6 +60,16 @@ func (h *HTTPHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 표준 Idempotency-Key 헤더가 있으면 본문의 키보다 우선 (응답 재생은 idempotency.Middleware 가 담당)
+	if key := r.Header.Get(idempotency.HeaderIdempotencyKey); key != "" {
+		req.IdempotencyKey = key
+	}
+
+	if len(req.IdempotencyKey) > idempotency.MaxKeyLength {
+		h.respondError(w, http.StatusBadRequest, "idempotency key is too long", string(errors.ErrCodeInvalidOrder))
+		return
+	}
+
 	// IdempotencyKey가 없으면 생성
 	if req.IdempotencyKey == "" {
 		req.IdempotencyKey = uuid.New().String()
@@ -71,8 +84,14 @@ func (h *HTTPHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.orderService.CreateOrder(r.Context(), cmd)
 	if err != nil {
+		// 비즈니스 에러만 메시지를 돌려주고, 내부 에러(DB 등)의 원인은 로그에만 남김
+		var domainErr *errors.DomainError
+		if errors.IsBusinessError(err) && stderrors.As(err, &domainErr) {
+			h.respondError(w, http.StatusBadRequest, domainErr.Message, string(domainErr.Code))
+			return
+		}
 		h.logger.Error("failed to create order", zap.Error(err))
-		h.respondError(w, http.StatusInternalServerError, err.Error(), "")
+		h.respondError(w, http.StatusInternalServerError, "failed to create order", string(errors.ErrCodeInternalError))
 		return
 	}
 
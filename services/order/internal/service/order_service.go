@@ -3,13 +3,13 @@ package service
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/kyungseok/msa-saga-go-examples/common/errors"
 	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
 	"github.com/kyungseok/msa-saga-go-examples/services/order/internal/domain"
 	"github.com/kyungseok/msa-saga-go-examples/services/order/internal/repository"
 	"go.uber.org/zap"
@@ -44,7 +44,7 @@ type OrderService interface {
 type orderService struct {
 	db         *sql.DB
 	orderRepo  repository.OrderRepository
-	outboxRepo repository.OutboxRepository
+	outboxRepo outbox.Repository
 	logger     *zap.Logger
 }
 
@@ -52,7 +52,7 @@ type orderService struct {
 func NewOrderService(
 	db *sql.DB,
 	orderRepo repository.OrderRepository,
-	outboxRepo repository.OutboxRepository,
+	outboxRepo outbox.Repository,
 	logger *zap.Logger,
 ) OrderService {
 	return &orderService{
@@ -126,23 +126,9 @@ func (s *orderService) CreateOrder(ctx context.Context, cmd CreateOrderCommand)
 		Quantity: order.Quantity,
 	}
 
-	payload, err := json.Marshal(event)
-	if err != nil {
-		return nil, errors.Wrap(errors.ErrCodeSerializationError, "failed to marshal event", err)
-	}
-
 	// Outbox에 이벤트 저장 (트랜잭션과 함께 커밋)
-	outboxEvent := &repository.OutboxEvent{
-		AggregateType: "order",
-		AggregateID:   order.ID,
-		EventType:     string(events.EventOrderCreated),
-		Payload:       payload,
-		Status:        "PENDING",
-		CreatedAt:     now,
-	}
-
-	if err := s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
-		return nil, errors.Wrap(errors.ErrCodeDatabaseError, "failed to insert outbox event", err)
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "order", ID: order.ID}, event); err != nil {
+		return nil, err
 	}
 
 	// 트랜잭션 커밋
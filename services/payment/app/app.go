@@ -0,0 +1,58 @@
+// Package app 결제 서비스 구성 요소 조립
+//
+// cmd/main.go 와 네 서비스를 한 프로세스에서 메모리 브로커로 묶는 services/local 이 함께 사용한다.
+// 메시징 백엔드, Outbox 발행, HTTP 서버 같은 실행 환경은 호출자가 구성한다.
+package app
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kyungseok/msa-saga-go-examples/common/idempotency"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
+	"github.com/kyungseok/msa-saga-go-examples/common/quarantine"
+	"github.com/kyungseok/msa-saga-go-examples/services/payment/internal/handler"
+	"github.com/kyungseok/msa-saga-go-examples/services/payment/internal/repository"
+	"github.com/kyungseok/msa-saga-go-examples/services/payment/internal/service"
+	"go.uber.org/zap"
+)
+
+// App 결제 서비스
+type App struct {
+	// OutboxRepo 결제 서비스 Outbox (발행 워커, 보존 워커, 관리 API 에서 사용)
+	OutboxRepo outbox.Repository
+	// Quarantine 디코딩 실패 메시지 격리 저장소
+	Quarantine *quarantine.PostgresStore
+	// Processed 결제 처리 기록 (비즈니스 트랜잭션과 함께 커밋, 만료 기록은 StartSweeper 로 정리)
+	Processed *idempotency.PostgresStore
+
+	eventHandler *handler.EventHandler
+}
+
+// New 결제 서비스 조립
+func New(db *sql.DB, idemStore idempotency.Store, logger *zap.Logger) *App {
+	paymentRepo := repository.NewPaymentRepository(db)
+	outboxRepo := outbox.NewRepository(db)
+	processedStore := idempotency.NewPostgresStore(db)
+	quarantineStore := quarantine.NewPostgresStore(db)
+
+	paymentService := service.NewPaymentService(db, paymentRepo, outboxRepo, logger)
+
+	return &App{
+		OutboxRepo:   outboxRepo,
+		Quarantine:   quarantineStore,
+		Processed:    processedStore,
+		eventHandler: handler.NewEventHandler(paymentService, idemStore, processedStore, quarantineStore, logger),
+	}
+}
+
+// Topics 구독할 토픽 목록
+func (a *App) Topics() []string {
+	return a.eventHandler.Topics()
+}
+
+// HandleMessage 메시지 처리
+func (a *App) HandleMessage(ctx context.Context, msg *messaging.Message) error {
+	return a.eventHandler.HandleMessage(ctx, msg)
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/kyungseok/msa-saga-go-examples/common/events"
@@ -14,80 +13,35 @@ import (
 
 // EventHandler 이벤트 핸들러
 type EventHandler struct {
-	paymentService service.PaymentService
-	idemStore      idempotency.Store
-	logger         *zap.Logger
+	router *messaging.Router
 }
 
 // NewEventHandler 이벤트 핸들러 생성
 func NewEventHandler(
 	paymentService service.PaymentService,
 	idemStore idempotency.Store,
+	processedStore *idempotency.PostgresStore,
+	quarantine messaging.Quarantine,
 	logger *zap.Logger,
 ) *EventHandler {
-	return &EventHandler{
-		paymentService: paymentService,
-		idemStore:      idemStore,
-		logger:         logger,
-	}
-}
+	router := messaging.NewRouter(logger,
+		messaging.WithIdempotency(idemStore, 24*time.Hour),
+		messaging.WithQuarantine(quarantine))
 
-// HandleMessage 메시지 처리
-func (h *EventHandler) HandleMessage(ctx context.Context, msg *messaging.Message) error {
-	h.logger.Info("received message",
-		zap.String("topic", msg.Topic),
-		zap.Int64("offset", msg.Offset))
+	// 결제는 처리 기록(processed_events)과 결제/Outbox 쓰기를 한 트랜잭션으로 커밋
+	messaging.On[events.OrderCreatedEvent](router,
+		idempotency.Transactional(processedStore, 24*time.Hour, paymentService.HandleOrderCreated))
+	messaging.On[events.StockReservationFailedEvent](router, paymentService.HandleStockReservationFailed)
 
-	// 이벤트 타입에 따라 분기
-	switch events.EventType(msg.Topic) {
-	case events.EventOrderCreated:
-		return h.handleOrderCreated(ctx, msg)
-	case events.EventStockReservationFailed:
-		return h.handleStockReservationFailed(ctx, msg)
-	default:
-		h.logger.Warn("unknown event type", zap.String("topic", msg.Topic))
-		return nil
-	}
+	return &EventHandler{router: router}
 }
 
-func (h *EventHandler) handleOrderCreated(ctx context.Context, msg *messaging.Message) error {
-	var evt events.OrderCreatedEvent
-	if err := json.Unmarshal(msg.Value, &evt); err != nil {
-		return err
-	}
-
-	// 멱등성 체크
-	if processed, _ := h.idemStore.IsProcessed(ctx, evt.EventID); processed {
-		h.logger.Info("event already processed", zap.String("eventId", evt.EventID))
-		return nil
-	}
-
-	if err := h.paymentService.HandleOrderCreated(ctx, evt); err != nil {
-		return err
-	}
-
-	// 처리 완료 표시
-	_, _ = h.idemStore.Reserve(ctx, evt.EventID, 24*time.Hour)
-	return nil
+// Topics 구독할 토픽 목록
+func (h *EventHandler) Topics() []string {
+	return h.router.Topics()
 }
 
-func (h *EventHandler) handleStockReservationFailed(ctx context.Context, msg *messaging.Message) error {
-	var evt events.StockReservationFailedEvent
-	if err := json.Unmarshal(msg.Value, &evt); err != nil {
-		return err
-	}
-
-	// 멱등성 체크
-	if processed, _ := h.idemStore.IsProcessed(ctx, evt.EventID); processed {
-		h.logger.Info("event already processed", zap.String("eventId", evt.EventID))
-		return nil
-	}
-
-	if err := h.paymentService.HandleStockReservationFailed(ctx, evt); err != nil {
-		return err
-	}
-
-	// 처리 완료 표시
-	_, _ = h.idemStore.Reserve(ctx, evt.EventID, 24*time.Hour)
-	return nil
+// HandleMessage 메시지 처리
+func (h *EventHandler) HandleMessage(ctx context.Context, msg *messaging.Message) error {
+	return h.router.Handle(ctx, msg)
 }
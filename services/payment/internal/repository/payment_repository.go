@@ -11,7 +11,7 @@ import (
 
 // PaymentRepository 결제 레포지토리 인터페이스
 type PaymentRepository interface {
-	Create(ctx context.Context, payment *domain.Payment) error
+	Create(ctx context.Context, tx *sql.Tx, payment *domain.Payment) error
 	FindByID(ctx context.Context, id int64) (*domain.Payment, error)
 	FindByOrderID(ctx context.Context, orderID int64) (*domain.Payment, error)
 	FindByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error)
@@ -28,14 +28,14 @@ func NewPaymentRepository(db *sql.DB) PaymentRepository {
 }
 
 // Create 결제 생성
-func (r *paymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+func (r *paymentRepository) Create(ctx context.Context, tx *sql.Tx, payment *domain.Payment) error {
 	query := `
 		INSERT INTO payments (order_id, amount, payment_type, status, idempotency_key, payment_gateway_tx_id, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 
-	err := r.db.QueryRowContext(
+	err := tx.QueryRowContext(
 		ctx,
 		query,
 		payment.OrderID,
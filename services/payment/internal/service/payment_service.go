@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"math/rand"
 	"time"
@@ -11,6 +10,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/kyungseok/msa-saga-go-examples/common/errors"
 	"github.com/kyungseok/msa-saga-go-examples/common/events"
+	"github.com/kyungseok/msa-saga-go-examples/common/messaging"
+	"github.com/kyungseok/msa-saga-go-examples/common/outbox"
+	"github.com/kyungseok/msa-saga-go-examples/common/retry"
 	"github.com/kyungseok/msa-saga-go-examples/services/payment/internal/domain"
 	"github.com/kyungseok/msa-saga-go-examples/services/payment/internal/repository"
 	"go.uber.org/zap"
@@ -18,7 +20,8 @@ import (
 
 // PaymentService 결제 서비스 인터페이스
 type PaymentService interface {
-	HandleOrderCreated(ctx context.Context, evt events.OrderCreatedEvent) error
+	// HandleOrderCreated 호출자가 연 트랜잭션 안에서 결제 처리 (idempotency.Transactional 로 감싸 처리 기록과 함께 커밋)
+	HandleOrderCreated(ctx context.Context, tx *sql.Tx, evt events.OrderCreatedEvent) error
 	HandleStockReservationFailed(ctx context.Context, evt events.StockReservationFailedEvent) error
 	GetPayment(ctx context.Context, paymentID int64) (*domain.Payment, error)
 }
@@ -26,15 +29,16 @@ type PaymentService interface {
 type paymentService struct {
 	db          *sql.DB
 	paymentRepo repository.PaymentRepository
-	outboxRepo  repository.OutboxRepository
+	outboxRepo  outbox.Repository
 	logger      *zap.Logger
+	breaker     *retry.CircuitBreaker
 }
 
 // NewPaymentService 결제 서비스 생성
 func NewPaymentService(
 	db *sql.DB,
 	paymentRepo repository.PaymentRepository,
-	outboxRepo repository.OutboxRepository,
+	outboxRepo outbox.Repository,
 	logger *zap.Logger,
 ) PaymentService {
 	return &paymentService{
@@ -42,11 +46,12 @@ func NewPaymentService(
 		paymentRepo: paymentRepo,
 		outboxRepo:  outboxRepo,
 		logger:      logger,
+		breaker:     retry.NewCircuitBreaker(retry.DefaultCircuitBreakerConfig()),
 	}
 }
 
 // HandleOrderCreated 주문 생성 이벤트 처리 (결제 실행)
-func (s *paymentService) HandleOrderCreated(ctx context.Context, evt events.OrderCreatedEvent) error {
+func (s *paymentService) HandleOrderCreated(ctx context.Context, tx *sql.Tx, evt events.OrderCreatedEvent) error {
 	s.logger.Info("handling order created event",
 		zap.Int64("orderId", evt.OrderID),
 		zap.String("correlationId", evt.CorrelationID))
@@ -63,16 +68,14 @@ func (s *paymentService) HandleOrderCreated(ctx context.Context, evt events.Orde
 		return nil
 	}
 
-	// 트랜잭션 시작
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to begin transaction", err)
-	}
-	defer tx.Rollback()
-
 	// 결제 처리 (외부 결제 게이트웨이 호출 시뮬레이션)
 	paymentResult, err := s.processPayment(ctx, evt.OrderID, evt.Amount)
 	if err != nil {
+		// 게이트웨이 장애는 결제 거절이 아니므로 보상 이벤트 없이 재처리
+		if !errors.IsBusinessError(err) {
+			return err
+		}
+
 		// 결제 실패 이벤트 발행
 		return s.publishPaymentFailed(ctx, tx, evt, err.Error())
 	}
@@ -90,7 +93,7 @@ func (s *paymentService) HandleOrderCreated(ctx context.Context, evt events.Orde
 		UpdatedAt:          now,
 	}
 
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+	if err := s.paymentRepo.Create(ctx, tx, payment); err != nil {
 		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to create payment", err)
 	}
 
@@ -102,6 +105,7 @@ func (s *paymentService) HandleOrderCreated(ctx context.Context, evt events.Orde
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID:     evt.OrderID,
 		PaymentID:   payment.ID,
@@ -109,27 +113,8 @@ func (s *paymentService) HandleOrderCreated(ctx context.Context, evt events.Orde
 		PaymentType: payment.PaymentType,
 	}
 
-	payload, err := json.Marshal(paymentCompletedEvt)
-	if err != nil {
-		return errors.Wrap(errors.ErrCodeSerializationError, "failed to marshal event", err)
-	}
-
-	outboxEvent := &repository.OutboxEvent{
-		AggregateType: "payment",
-		AggregateID:   payment.ID,
-		EventType:     string(events.EventPaymentCompleted),
-		Payload:       payload,
-		Status:        "PENDING",
-		CreatedAt:     now,
-	}
-
-	if err := s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
-		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to insert outbox event", err)
-	}
-
-	// 트랜잭션 커밋
-	if err := tx.Commit(); err != nil {
-		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to commit transaction", err)
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "payment", ID: payment.ID}, paymentCompletedEvt); err != nil {
+		return err
 	}
 
 	s.logger.Info("payment completed",
@@ -190,28 +175,15 @@ func (s *paymentService) HandleStockReservationFailed(ctx context.Context, evt e
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID:   evt.OrderID,
 		PaymentID: payment.ID,
 		Amount:    payment.Amount,
 	}
 
-	payload, err := json.Marshal(paymentRefundedEvt)
-	if err != nil {
-		return errors.Wrap(errors.ErrCodeSerializationError, "failed to marshal event", err)
-	}
-
-	outboxEvent := &repository.OutboxEvent{
-		AggregateType: "payment",
-		AggregateID:   payment.ID,
-		EventType:     string(events.EventPaymentRefunded),
-		Payload:       payload,
-		Status:        "PENDING",
-		CreatedAt:     now,
-	}
-
-	if err := s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
-		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to insert outbox event", err)
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "payment", ID: payment.ID}, paymentRefundedEvt); err != nil {
+		return err
 	}
 
 	// 트랜잭션 커밋
@@ -232,11 +204,27 @@ func (s *paymentService) GetPayment(ctx context.Context, paymentID int64) (*doma
 }
 
 // processPayment 결제 처리 (외부 결제 게이트웨이 호출 시뮬레이션)
+//
+// 게이트웨이 장애가 이어져 서킷이 열리면 back-pressure 에러를 반환해 컨슈머가 파티션을 멈추게 한다.
 func (s *paymentService) processPayment(ctx context.Context, orderID, amount int64) (*PaymentResult, error) {
+	if ok, retryAfter := s.breaker.Allow(); !ok {
+		return nil, messaging.Backpressure(retryAfter,
+			errors.New(errors.ErrCodeNetworkError, "payment gateway circuit is open"))
+	}
+
 	// 실제로는 외부 결제 게이트웨이 API를 호출
-	// 여기서는 시뮬레이션: 10% 확률로 결제 실패
+	// 여기서는 시뮬레이션: 2% 확률로 게이트웨이 장애, 10% 확률로 결제 실패
 	time.Sleep(100 * time.Millisecond) // 네트워크 지연 시뮬레이션
 
+	if rand.Intn(100) < 2 {
+		s.breaker.Failure()
+		if s.breaker.State() == retry.CircuitOpen {
+			s.logger.Warn("payment gateway circuit opened", zap.Int64("orderId", orderID))
+		}
+		return nil, errors.New(errors.ErrCodeNetworkError, "payment gateway unavailable")
+	}
+	s.breaker.Success()
+
 	if rand.Intn(100) < 10 {
 		return nil, errors.New(errors.ErrCodePaymentDeclined, "payment declined by gateway")
 	}
@@ -272,38 +260,22 @@ func (s *paymentService) publishPaymentFailed(
 			SchemaVersion: 1,
 			OccurredAt:    now,
 			CorrelationID: evt.CorrelationID,
+			CausationID:   evt.EventID,
 		},
 		OrderID: evt.OrderID,
 		Reason:  reason,
 	}
 
-	payload, err := json.Marshal(paymentFailedEvt)
-	if err != nil {
-		return errors.Wrap(errors.ErrCodeSerializationError, "failed to marshal event", err)
-	}
-
-	outboxEvent := &repository.OutboxEvent{
-		AggregateType: "payment",
-		AggregateID:   evt.OrderID,
-		EventType:     string(events.EventPaymentFailed),
-		Payload:       payload,
-		Status:        "PENDING",
-		CreatedAt:     now,
-	}
-
-	if err := s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
-		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to insert outbox event", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return errors.Wrap(errors.ErrCodeDatabaseError, "failed to commit transaction", err)
+	if err := s.outboxRepo.Enqueue(ctx, tx, outbox.Aggregate{Type: "payment", ID: evt.OrderID}, paymentFailedEvt); err != nil {
+		return err
 	}
 
 	s.logger.Warn("payment failed event published",
 		zap.Int64("orderId", evt.OrderID),
 		zap.String("reason", reason))
 
-	return fmt.Errorf("payment failed: %s", reason)
+	// 결제 거절은 보상 이벤트로 처리가 끝난 것이므로 처리 기록과 함께 커밋되도록 성공으로 반환
+	return nil
 }
 
 // PaymentResult 결제 결과